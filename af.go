@@ -1,7 +1,10 @@
 package wann
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dave/jennifer/jen"
@@ -58,20 +61,66 @@ var ActivationFunctions = map[ActivationFunctionIndex](func(float64) float64){
 	SoftPlus: af.SoftPlus,   // SoftPlus
 }
 
-// ComplexityEstimate is a map for having an estimate of how complex each function is,
-// based on a quick benchmark of each function.
-// The complexity estimates will vary, depending on the performance.
-var ComplexityEstimate = make(map[ActivationFunctionIndex]float64)
+// constantNames are the names of the constants above, used when generating Go source code
+var constantNames = map[ActivationFunctionIndex]string{
+	Step:     "Step",
+	Linear:   "Linear",
+	Sin:      "Sin",
+	Gauss:    "Gauss",
+	Tanh:     "Tanh",
+	Sigmoid:  "Sigmoid",
+	Inv:      "Inv",
+	Abs:      "Abs",
+	ReLU:     "ReLU",
+	Cos:      "Cos",
+	Squared:  "Squared",
+	Swish:    "Swish",
+	SoftPlus: "SoftPlus",
+}
+
+// DefaultComplexity is a fixed estimate of how complex each activation function is, where 1.0 is the most complex one.
+// It was made with BenchmarkComplexity (see cmd/complexity), so that the scores do not depend on the machine.
+var DefaultComplexity = map[ActivationFunctionIndex]float64{
+	Step:     0.20,
+	Linear:   0.20,
+	Sin:      0.62,
+	Gauss:    0.30,
+	Tanh:     0.52,
+	Sigmoid:  0.19,
+	Inv:      0.19,
+	Abs:      0.20,
+	ReLU:     0.20,
+	Cos:      0.66,
+	Squared:  0.22,
+	Swish:    0.21,
+	SoftPlus: 1.00,
+}
 
-func (config *Config) estimateComplexity() {
-	if config.Verbose {
-		fmt.Print("Estimating activation function complexity...")
+// ComplexityEstimate is a map for having an estimate of how complex each function is.
+// It starts out as a copy of DefaultComplexity, and is used for measuring the complexity of networks,
+// for the activation functions that are not in Config.ActivationFunctionCosts.
+var ComplexityEstimate = func() map[ActivationFunctionIndex]float64 {
+	estimate := make(map[ActivationFunctionIndex]float64, len(DefaultComplexity))
+	for afi, complexity := range DefaultComplexity {
+		estimate[afi] = complexity
 	}
-	startEstimate := time.Now()
+	return estimate
+}()
+
+// BenchmarkComplexity benchmarks each activation function, and returns how long each one
+// takes, where 1.0 is the slowest one. The results vary between machines and between runs,
+// so they are not used when evolving, but they can be used for updating DefaultComplexity.
+func BenchmarkComplexity() map[ActivationFunctionIndex]float64 {
 	resolution := 0.0001
 	durationMap := make(map[ActivationFunctionIndex]time.Duration)
 	var maxDuration time.Duration
-	for i, f := range ActivationFunctions {
+	activationFunctionsMut.RLock()
+	functions := make(map[ActivationFunctionIndex]func(float64) float64, len(ActivationFunctions))
+	for afi, f := range ActivationFunctions {
+		functions[afi] = f
+	}
+	activationFunctionsMut.RUnlock()
+	for i, f := range functions {
 		start := time.Now()
 		for x := 0.0; x <= 1.0; x += resolution {
 			_ = f(x)
@@ -82,20 +131,42 @@ func (config *Config) estimateComplexity() {
 			maxDuration = duration
 		}
 	}
-	for i := range ActivationFunctions {
+	estimate := make(map[ActivationFunctionIndex]float64, len(functions))
+	for i := range functions {
 		// 1.0 means the function took maxDuration
-		ComplexityEstimate[ActivationFunctionIndex(i)] = float64(durationMap[ActivationFunctionIndex(i)]) / float64(maxDuration)
+		estimate[ActivationFunctionIndex(i)] = float64(durationMap[ActivationFunctionIndex(i)]) / float64(maxDuration)
 	}
-	estimateDuration := time.Since(startEstimate)
-	if config.Verbose {
-		fmt.Printf(" done. (In %v)\n", estimateDuration)
+	return estimate
+}
+
+// ComplexityTable formats the given complexity estimates as Go source code, like DefaultComplexity,
+// sorted by activation function and rounded to two decimals
+func ComplexityTable(estimate map[ActivationFunctionIndex]float64) string {
+	afis := make([]ActivationFunctionIndex, 0, len(estimate))
+	for afi := range estimate {
+		afis = append(afis, afi)
+	}
+	sort.Slice(afis, func(i, j int) bool {
+		return afis[i] < afis[j]
+	})
+	var sb strings.Builder
+	sb.WriteString("var DefaultComplexity = map[ActivationFunctionIndex]float64{\n")
+	for _, afi := range afis {
+		name, ok := constantNames[afi]
+		if !ok {
+			// A registered activation function, see RegisterActivationFunction
+			name = fmt.Sprintf("ActivationFunctionIndex(%d)", afi)
+		}
+		fmt.Fprintf(&sb, "\t%-9s %.2f,\n", name+":", estimate[afi])
 	}
+	sb.WriteString("}\n")
+	return sb.String()
 }
 
 // Call runs an activation function with the given float64 value.
 // The activation function is chosen by one of the constants above.
 func (afi ActivationFunctionIndex) Call(x float64) float64 {
-	if f, ok := ActivationFunctions[afi]; ok {
+	if f, ok := activationFunction(afi); ok {
 		return f(x)
 	}
 	// Use the linear function by default
@@ -104,6 +175,13 @@ func (afi ActivationFunctionIndex) Call(x float64) float64 {
 
 // Name returns a name for each activation function
 func (afi ActivationFunctionIndex) Name() string {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	return afi.name()
+}
+
+// name returns a name for each activation function, while activationFunctionsMut is held
+func (afi ActivationFunctionIndex) name() string {
 	switch afi {
 	case Step:
 		return "Step"
@@ -132,10 +210,30 @@ func (afi ActivationFunctionIndex) Name() string {
 	case SoftPlus:
 		return "SoftPlus"
 	default:
+		if caf, ok := customActivationFunctions[afi]; ok {
+			return caf.Name
+		}
 		return "Untitled"
 	}
 }
 
+// ActivationFunctionByName returns the activation function with the given name, as returned by the Name method
+func ActivationFunctionByName(name string) (ActivationFunctionIndex, error) {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	return activationFunctionByName(name)
+}
+
+// activationFunctionByName returns the activation function with the given name, while activationFunctionsMut is held
+func activationFunctionByName(name string) (ActivationFunctionIndex, error) {
+	for afi := range ActivationFunctions {
+		if afi.name() == name {
+			return afi, nil
+		}
+	}
+	return Linear, errors.New("unknown activation function: " + name)
+}
+
 // goExpression returns the Go expression for this activation function, using the given variable name string as the input variable name
 func (afi ActivationFunctionIndex) goExpression(varName string) string {
 	switch afi {
@@ -168,6 +266,9 @@ func (afi ActivationFunctionIndex) goExpression(varName string) string {
 	case SoftPlus:
 		return "math.Log(1.0 + math.Exp(" + varName + "))"
 	default:
+		if caf, ok := customActivationFunction(afi); ok {
+			return caf.customExpression(varName)
+		}
 		return varName
 	}
 }
@@ -235,14 +336,17 @@ func (afi ActivationFunctionIndex) Statement(inner *jen.Statement) *jen.Statemen
 		return jen.Qual("math", "Pow").Call(inner, jen.Lit(2.0))
 	case Swish:
 		// (inner / (1.0 + math.Exp(-inner)))
-		return jen.Parens(inner.Op("/").Parens(jen.Lit(1.0).Op("+").Qual("math", "Exp").Call(jen.Op("-").Parens(inner))))
+		return jen.Parens(jen.Add(inner).Op("/").Parens(jen.Lit(1.0).Op("+").Qual("math", "Exp").Call(jen.Op("-").Parens(inner))))
 	case SoftPlus:
 		// math.Log(1.0 + math.Exp(inner))
 		return jen.Qual("math", "Log").Call(jen.Lit(1.0).Op("+").Qual("math", "Exp").Call(inner))
 	case Linear:
 		// This is also the default case: (inner)
-		fallthrough
+		return jen.Parens(inner)
 	default:
+		if caf, ok := customActivationFunction(afi); ok {
+			return caf.customStatement(inner)
+		}
 		// (inner)
 		return jen.Parens(inner)
 	}
@@ -252,3 +356,61 @@ func (afi ActivationFunctionIndex) Statement(inner *jen.Statement) *jen.Statemen
 func (afi ActivationFunctionIndex) GoRun(x float64) (float64, error) {
 	return RunStatementX(afi.Statement(jen.Id("x")), x)
 }
+
+// randomActivationFunction returns a random activation function, among the ones that are allowed for this network
+func (net *Network) randomActivationFunction() ActivationFunctionIndex {
+	if len(net.allowed) > 0 {
+		return net.allowed[net.random().Intn(len(net.allowed))]
+	}
+	return ActivationFunctionIndex(net.random().Intn(activationFunctionCount()))
+}
+
+// SetAllowedActivationFunctions sets which activation functions may be chosen when this network is modified.
+// All of the ActivationFunctions may be chosen if none are given.
+func (net *Network) SetAllowedActivationFunctions(afis ...ActivationFunctionIndex) {
+	net.allowed = afis
+}
+
+// isAllowed checks if the given activation function may be used by this network
+func (net *Network) isAllowed(afi ActivationFunctionIndex) bool {
+	if len(net.allowed) == 0 {
+		return true
+	}
+	for _, allowedAfi := range net.allowed {
+		if allowedAfi == afi {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAllowedActivationFunctions checks that all of config.AllowedActivationFunctions exist
+func (config *Config) checkAllowedActivationFunctions() error {
+	for _, afi := range config.AllowedActivationFunctions {
+		if _, ok := activationFunction(afi); !ok {
+			return fmt.Errorf("unknown activation function in the allowed activation functions: %d", afi)
+		}
+	}
+	return nil
+}
+
+// checkActivationFunctions checks that the given network only uses activation functions that are allowed by the configuration.
+// The input nodes and the bias node are not checked, since their activation functions are never used.
+func (config *Config) checkActivationFunctions(net *Network) error {
+	if len(config.AllowedActivationFunctions) == 0 {
+		return nil
+	}
+	allowed := make(map[ActivationFunctionIndex]bool, len(config.AllowedActivationFunctions))
+	for _, afi := range config.AllowedActivationFunctions {
+		allowed[afi] = true
+	}
+	for i, node := range net.AllNodes {
+		if net.IsInput(NeuronIndex(i)) || net.IsBias(NeuronIndex(i)) {
+			continue
+		}
+		if !allowed[node.ActivationFunction] {
+			return fmt.Errorf("node %d uses the %s activation function, which is not allowed", i, node.ActivationFunction.Name())
+		}
+	}
+	return nil
+}
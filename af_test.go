@@ -2,6 +2,7 @@ package wann
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/dave/jennifer/jen"
 )
@@ -36,3 +37,69 @@ func ExampleActivationFunctionIndex_GoRun() {
 	// 0.8824699625576026
 	// 0.8824969025845955
 }
+
+func ExampleComplexityTable() {
+	fmt.Print(ComplexityTable(map[ActivationFunctionIndex]float64{Linear: 0.2, Gauss: 0.304, SoftPlus: 1.0}))
+	// Output:
+	// var DefaultComplexity = map[ActivationFunctionIndex]float64{
+	// 	Linear:   0.20,
+	// 	Gauss:    0.30,
+	// 	SoftPlus: 1.00,
+	// }
+}
+
+func TestBenchmarkComplexity(t *testing.T) {
+	estimate := BenchmarkComplexity()
+	if len(estimate) != len(ActivationFunctions) {
+		t.Fatalf("expected an estimate for each of the %d activation functions, got %d", len(ActivationFunctions), len(estimate))
+	}
+	for afi, complexity := range estimate {
+		if complexity <= 0.0 || complexity > 1.0 {
+			t.Errorf("expected the complexity of %s to be in (0, 1], got %f", afi.Name(), complexity)
+		}
+		if _, ok := DefaultComplexity[afi]; !ok {
+			t.Errorf("%s is missing from DefaultComplexity", afi.Name())
+		}
+	}
+}
+
+func TestAllowedActivationFunctions(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	allowed := map[ActivationFunctionIndex]bool{Linear: true, ReLU: true, Step: true, Abs: true}
+	checkNetwork := func(net *Network) {
+		for i, node := range net.AllNodes {
+			if !allowed[node.ActivationFunction] {
+				t.Fatalf("node %d uses %s, which is not allowed", i, node.ActivationFunction.Name())
+			}
+		}
+	}
+	config := &Config{
+		InitialConnectionRatio:     0.5,
+		Generations:                10,
+		PopulationSize:             40,
+		RandomSeed:                 commonSeed,
+		AllowedActivationFunctions: []ActivationFunctionIndex{Linear, ReLU, Step, Abs},
+		OnGeneration: func(stats GenerationStats) {
+			checkNetwork(stats.BestNetwork)
+		},
+	}
+	net, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNetwork(net)
+	for i := 0; i < 20; i++ {
+		net.RandomizeActivationFunctionForRandomNeuron()
+		net.Modify(10)
+	}
+	checkNetwork(net)
+
+	config.AllowedActivationFunctions = []ActivationFunctionIndex{ActivationFunctionIndex(len(ActivationFunctions))}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err == nil {
+		t.Error("expected an error for an unknown activation function")
+	}
+}
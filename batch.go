@@ -0,0 +1,214 @@
+package wann
+
+import (
+	"fmt"
+)
+
+// EvaluateBatch evaluates the network for each row of input values, and returns the value of the first output node
+// for each row. The network is compiled once, which gives the same results as Evaluate, and each node
+// is evaluated for all the rows before the next node. The .Value fields of the nodes are not changed.
+// If a row does not have one value per input node, the rows are evaluated one by one with Evaluate instead,
+// which uses the .Value fields of the input nodes for missing values.
+func (net *Network) EvaluateBatch(inputs [][]float64) []float64 {
+	c := net.Compile()
+	if !c.rowsFit(inputs) {
+		results := make([]float64, len(inputs))
+		for row, inputValues := range inputs {
+			results[row] = net.Evaluate(inputValues)
+		}
+		return results
+	}
+	results, _ := c.EvaluateBatch(inputs)
+	return results
+}
+
+// EvaluateColumns is like EvaluateBatch, but takes one column of values per input node, where
+// all the columns have the same length. This avoids copying the input values into rows, for column-major data.
+func (net *Network) EvaluateColumns(columns [][]float64) []float64 {
+	c := net.Compile()
+	if !c.columnsFit(columns) {
+		return net.EvaluateBatch(transpose(columns))
+	}
+	results, _ := c.EvaluateColumns(columns)
+	return results
+}
+
+// EvaluateAllBatch evaluates the network for each row of input values,
+// and returns one result per output node for each row, like EvaluateBatch does for the first output node.
+func (net *Network) EvaluateAllBatch(inputs [][]float64) [][]float64 {
+	c := net.Compile()
+	if !c.rowsFit(inputs) {
+		results := make([][]float64, len(inputs))
+		for row, inputValues := range inputs {
+			results[row] = net.EvaluateAll(inputValues)
+		}
+		return results
+	}
+	results, _ := c.EvaluateAllBatch(inputs)
+	return results
+}
+
+// ClassifyBatch returns the class label for each row of input values, like Classify
+func (net *Network) ClassifyBatch(inputs [][]float64) []int {
+	results := net.EvaluateAllBatch(inputs)
+	labels := make([]int, len(results))
+	for i, outputs := range results {
+		labels[i] = Argmax(outputs)
+	}
+	return labels
+}
+
+// EvaluateBatch evaluates the compiled network for each row of input values,
+// and returns the value of the first output node for each row.
+// Returns an error if a row does not have one value per input node.
+func (c *CompiledNetwork) EvaluateBatch(inputs [][]float64) ([]float64, error) {
+	if err := c.checkRows(inputs); err != nil {
+		return nil, err
+	}
+	results := make([]float64, len(inputs))
+	values := c.runBatch(c.ends[0], len(inputs), inputs, nil, c.net.Weight)
+	c.batchResults(values, 0, len(inputs), results)
+	return results, nil
+}
+
+// EvaluateColumns evaluates the compiled network for column-major input values, with one column per input node,
+// and returns the value of the first output node for each row.
+// Returns an error if there is not one column per input node, or if the columns have different lengths.
+func (c *CompiledNetwork) EvaluateColumns(columns [][]float64) ([]float64, error) {
+	if !c.columnsFit(columns) {
+		return nil, fmt.Errorf("expected %d columns of the same length, got %d", c.inputs, len(columns))
+	}
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0])
+	}
+	results := make([]float64, rows)
+	values := c.runBatch(c.ends[0], rows, nil, columns, c.net.Weight)
+	c.batchResults(values, 0, rows, results)
+	return results, nil
+}
+
+// EvaluateAllBatch evaluates the compiled network for each row of input values,
+// and returns one result per output node for each row.
+// Returns an error if a row does not have one value per input node.
+func (c *CompiledNetwork) EvaluateAllBatch(inputs [][]float64) ([][]float64, error) {
+	if err := c.checkRows(inputs); err != nil {
+		return nil, err
+	}
+	results := make([][]float64, len(inputs))
+	// Store all the results in one slice
+	all := make([]float64, len(inputs)*len(c.outputs))
+	for row := range results {
+		results[row] = all[row*len(c.outputs) : (row+1)*len(c.outputs)]
+	}
+	values := c.runBatch(len(c.steps), len(inputs), inputs, nil, c.net.Weight)
+	column := make([]float64, len(inputs))
+	for i := range c.outputs {
+		c.batchResults(values, i, len(inputs), column)
+		for row := range results {
+			results[row][i] = column[row]
+		}
+	}
+	return results, nil
+}
+
+// checkRows returns an error for the first row that does not have one value per input node
+func (c *CompiledNetwork) checkRows(inputs [][]float64) error {
+	for row, inputValues := range inputs {
+		if err := c.checkInputValues(inputValues); err != nil {
+			return fmt.Errorf("row %d: %s", row, err)
+		}
+	}
+	return nil
+}
+
+// rowsFit checks if every row has one value per input node
+func (c *CompiledNetwork) rowsFit(inputs [][]float64) bool {
+	for _, inputValues := range inputs {
+		if len(inputValues) != c.inputs {
+			return false
+		}
+	}
+	return true
+}
+
+// columnsFit checks if there is one column per input node, and if all the columns have the same length
+func (c *CompiledNetwork) columnsFit(columns [][]float64) bool {
+	if len(columns) != c.inputs {
+		return false
+	}
+	for _, column := range columns {
+		if len(column) != len(columns[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// transpose converts column-major values to rows. Missing values in short columns are left out.
+func transpose(columns [][]float64) [][]float64 {
+	rows := 0
+	for _, column := range columns {
+		if len(column) > rows {
+			rows = len(column)
+		}
+	}
+	inputs := make([][]float64, rows)
+	for row := range inputs {
+		inputs[row] = make([]float64, 0, len(columns))
+		for _, column := range columns {
+			if row < len(column) {
+				inputs[row] = append(inputs[row], column[row])
+			}
+		}
+	}
+	return inputs
+}
+
+// runBatch performs the steps up to the given index for all the rows, using either row-major inputs or
+// column-major columns, and the given shared weight. It returns the results, where the result of step s
+// for a row is at s*rows+row.
+func (c *CompiledNetwork) runBatch(end, rows int, inputs, columns [][]float64, weight float64) []float64 {
+	values := make([]float64, end*rows)
+	for s, step := range c.steps[:end] {
+		stepValues := values[s*rows : (s+1)*rows]
+		switch step.kind {
+		case stepInput:
+			if columns != nil {
+				copy(stepValues, columns[step.input])
+			} else {
+				for row := range stepValues {
+					stepValues[row] = inputs[row][step.input]
+				}
+			}
+		case stepConstant:
+			for row := range stepValues {
+				stepValues[row] = step.constant
+			}
+		case stepActivation:
+			// Sum up the operands in the same order as CompiledNetwork.Evaluate, for the same results
+			for _, operand := range c.operands[step.first:step.last] {
+				operandValues := values[operand*rows : (operand+1)*rows]
+				for row := range stepValues {
+					stepValues[row] += operandValues[row] * weight
+				}
+			}
+			for row := range stepValues {
+				stepValues[row] = step.f(stepValues[row])
+			}
+		}
+	}
+	return values
+}
+
+// batchResults stores the results for the given output node and all the rows in results
+func (c *CompiledNetwork) batchResults(values []float64, output, rows int, results []float64) {
+	step := c.outputs[output]
+	if step < 0 {
+		for row := range results {
+			results[row] = 0.0
+		}
+		return
+	}
+	copy(results, values[step*rows:(step+1)*rows])
+}
@@ -0,0 +1,53 @@
+package wann
+
+import (
+	"testing"
+)
+
+func TestEvaluateBatch(t *testing.T) {
+	config := &Config{
+		inputs:                 4,
+		Outputs:                2,
+		InitialConnectionRatio: 0.6,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	inputs := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0},
+		{-0.5, 0.25, 1.0, 0.75},
+		{0.1, 0.2, 0.3, 0.4},
+	}
+	columns := transpose(inputs)
+	for n := 0; n < 10; n++ {
+		net := NewNetwork(config)
+		net.UpdateNetworkPointers()
+		for i := 0; i < 2*n; i++ {
+			net.Modify(10)
+		}
+		net.SetWeight(-1.5)
+		batch, byColumn, all := net.EvaluateBatch(inputs), net.EvaluateColumns(columns), net.EvaluateAllBatch(inputs)
+		labels := net.ClassifyBatch(inputs)
+		for row, inputValues := range inputs {
+			if expected := net.Evaluate(inputValues); batch[row] != expected || byColumn[row] != expected {
+				t.Fatalf("network %d, row %d: expected %v, got %v and %v", n, row, expected, batch[row], byColumn[row])
+			}
+			expected := net.EvaluateAll(inputValues)
+			for i := range expected {
+				if all[row][i] != expected[i] {
+					t.Fatalf("network %d, row %d: expected %v, got %v", n, row, expected, all[row])
+				}
+			}
+			if labels[row] != net.Classify(inputValues) {
+				t.Fatalf("network %d, row %d: expected class %d, got %d", n, row, net.Classify(inputValues), labels[row])
+			}
+		}
+	}
+	// Rows with the wrong number of values are evaluated one by one
+	net := NewNetwork(config)
+	short := [][]float64{{1.0, 0.5}, {0.0, 1.0, 0.0, 1.0}}
+	results := net.EvaluateBatch(short)
+	if len(results) != 2 || results[1] != net.Evaluate(short[1]) {
+		t.Errorf("unexpected results for rows of different lengths: %v", results)
+	}
+}
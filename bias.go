@@ -0,0 +1,34 @@
+package wann
+
+// addBiasNode adds a bias node to the network, with the value 1, and connects it to each
+// output node where a random number between 0 and 1 is at most the given ratio
+func (net *Network) addBiasNode(r float64) {
+	_, biasNodeIndex := net.newNeuron(net.innovations.node(biasRole))
+	net.AllNodes[biasNodeIndex].SetValue(1.0)
+	// The activation function of the bias node is never used, so use a fixed one.
+	// It is also left out when measuring the complexity and the compatibility distance.
+	net.AllNodes[biasNodeIndex].ActivationFunction = Linear
+	net.Bias = true
+	net.BiasNode = biasNodeIndex
+	for _, outputNodeIndex := range net.OutputNodes {
+		if r >= net.random().Float64() {
+			if err := net.AllNodes[outputNodeIndex].AddInput(biasNodeIndex); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// IsBias checks if the given node is the bias node
+func (net *Network) IsBias(ni NeuronIndex) bool {
+	return net.Bias && ni == net.BiasNode
+}
+
+// IsBias returns true if this is the bias node of the network
+// Returns false if nil
+func (neuron *Neuron) IsBias() bool {
+	if neuron.Net == nil {
+		return false
+	}
+	return neuron.Net.IsBias(neuron.neuronIndex)
+}
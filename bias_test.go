@@ -0,0 +1,140 @@
+package wann
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBias(t *testing.T) {
+	config := &Config{
+		inputs:                 2,
+		InitialConnectionRatio: 1.0,
+		Bias:                   true,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	if !net.Bias || !net.IsBias(net.BiasNode) || net.IsInput(net.BiasNode) || net.IsOutput(net.BiasNode) {
+		t.Fatal("expected a bias node that is neither an input nor an output node")
+	}
+	if !net.AllNodes[net.OutputNode].HasInput(net.BiasNode) {
+		t.Fatal("expected the bias node to be connected to the output node")
+	}
+	if net.AllNodes[net.BiasNode].ActivationFunction != Linear {
+		t.Error("expected the bias node to have a fixed activation function")
+	}
+	// The activation function of the bias node does not affect the complexity or the compatibility distance
+	changed := net.Copy()
+	changed.AllNodes[changed.BiasNode].ActivationFunction = Swish
+	weights := ComplexityWeights{ActivationFunctions: 1.0}
+	if a, b := net.weightedComplexity(weights, nil), changed.weightedComplexity(weights, nil); a != b {
+		t.Errorf("expected the same complexity, got %v and %v", a, b)
+	}
+	if d := CompatibilityDistance(&net, changed); d != 0.0 {
+		t.Errorf("expected a distance of 0, got %v", d)
+	}
+	net.AllNodes[net.OutputNode].ActivationFunction = Linear
+	net.SetWeight(0.5)
+	// Only the bias node contributes to the output
+	if result := net.Evaluate([]float64{0.0, 0.0}); result != 0.5 {
+		t.Errorf("expected 0.5, got %v", result)
+	}
+	inputData := [][]float64{{0.0, 0.0}, {1.0, 0.0}, {0.5, -1.0}}
+	for row, result := range net.EvaluateBatch(inputData) {
+		if expected := net.Evaluate(inputData[row]); result != expected {
+			t.Errorf("row %d: expected %v, got %v", row, expected, result)
+		}
+	}
+
+	statement, err := net.StatementWithInputDataVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(Render(statement), "1.0") {
+		t.Errorf("expected the bias value in the statement: %s", Render(statement))
+	}
+
+	var buf bytes.Buffer
+	if _, err := net.OutputSVG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "[b]") {
+		t.Error("expected the bias node to be labeled in the diagram")
+	}
+
+	data, err := json.Marshal(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Network
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsBias(net.BiasNode) {
+		t.Fatal("expected the loaded network to have the same bias node")
+	}
+	if a, b := net.Evaluate([]float64{1.0, 0.0}), loaded.Evaluate([]float64{1.0, 0.0}); a != b {
+		t.Errorf("expected %v, got %v", a, b)
+	}
+
+	// The bias node is never removed
+	for net.RemoveRandomHiddenNode() {
+	}
+	if !net.IsBias(net.BiasNode) || net.AllNodes[net.BiasNode].Value == nil {
+		t.Error("expected the bias node to be kept")
+	}
+}
+
+func TestEvolveBias(t *testing.T) {
+	// Both inputs are 0 for the first row, so a network without a bias node
+	// can not tell the rows apart with a step function that is centered at 0
+	inputData := [][]float64{{0.0, 0.0}, {0.0, 1.0}, {1.0, 0.0}, {1.0, 1.0}}
+	incorrectOutputMultipliers := []float64{1.0, -1.0, -1.0, -1.0}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         50,
+		Bias:                   true,
+		RandomSeed:             commonSeed,
+	}
+	net, err := config.Evolve(inputData, incorrectOutputMultipliers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !net.Bias {
+		t.Error("expected the evolved network to have a bias node")
+	}
+}
+
+func TestBiasAllowedActivationFunctions(t *testing.T) {
+	// The bias node is always linear, also when Linear is not one of the allowed activation functions
+	inputData := [][]float64{{0.0, 0.0}, {0.0, 1.0}, {1.0, 0.0}, {1.0, 1.0}}
+	const checkpointFile = "test_bias_checkpoint.json"
+	defer os.Remove(checkpointFile)
+	config := &Config{
+		InitialConnectionRatio:     0.5,
+		Generations:                3,
+		PopulationSize:             20,
+		Bias:                       true,
+		RandomSeed:                 commonSeed,
+		AllowedActivationFunctions: []ActivationFunctionIndex{ReLU, Step},
+		CheckpointFile:             checkpointFile,
+	}
+	net, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0, -1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := net.Save("test_bias.json"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("test_bias.json")
+	if _, err := config.LoadNetwork("test_bias.json"); err != nil {
+		t.Error(err)
+	}
+	if err := config.ResumeFrom(checkpointFile); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,165 @@
+package wann
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+)
+
+// checkpoint contains the entire state of a running evolution, after a generation has been completed
+type checkpoint struct {
+	Version              int               `json:"version"`
+	Generation           int               `json:"generation"`     // The next generation to evolve
+	Seed                 int64             `json:"seed"`           // The random seed of the evolution
+	GenerationSeed       int64             `json:"generationSeed"` // The seed for the pseudo-random number generator, at the start of the next generation
+	Population           []*Network        `json:"population"`
+	Distances            [][]int           `json:"distances"` // The distance from the output node, per node in the population
+	BestNetwork          *Network          `json:"bestNetwork"`
+	BestScores           []jsonFloat       `json:"bestScores"` // The best score for each generation so far
+	BestScore            jsonFloat         `json:"bestScore"`
+	WorstScore           jsonFloat         `json:"worstScore"`
+	NoImprovementCounter int               `json:"noImprovementCounter"`
+	Species              []*Network        `json:"species,omitempty"` // The representative of each species, if speciation is enabled
+	NextInnovation       int               `json:"nextInnovation"`
+	Innovations          []innovationSplit `json:"innovations"`     // The innovation numbers of inserted nodes
+	InnovationNodes      map[string]int    `json:"innovationNodes"` // The innovation numbers of the input, output and bias nodes
+	// The configured activation function costs, by name, since they affect the scores
+	ActivationFunctionCosts map[string]float64 `json:"activationFunctionCosts,omitempty"`
+}
+
+// jsonFloat is a float64 that can be encoded as JSON, also when it is infinite or NaN
+type jsonFloat float64
+
+// MarshalJSON encodes the number, using a string if it is infinite or NaN
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	x := float64(f)
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		return []byte(strconv.Quote(strconv.FormatFloat(x, 'g', -1, 64))), nil
+	}
+	return []byte(strconv.FormatFloat(x, 'g', -1, 64)), nil
+}
+
+// UnmarshalJSON decodes the number, that may be a string if it is infinite or NaN
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*f = jsonFloat(x)
+	return nil
+}
+
+// writeCheckpoint saves the given checkpoint to config.CheckpointFile.
+// The file is first written to a temporary file and then renamed, so that
+// an existing checkpoint is not lost if the program is stopped while writing.
+func (config *Config) writeCheckpoint(cp *checkpoint) error {
+	cp.Version = FormatVersion
+	cp.Distances = make([][]int, len(cp.Population))
+	for i, net := range cp.Population {
+		cp.Distances[i] = make([]int, len(net.AllNodes))
+		for j, node := range net.AllNodes {
+			cp.Distances[i][j] = node.distanceFromOutputNode
+		}
+	}
+	cp.Innovations, cp.InnovationNodes, cp.NextInnovation = config.innovationHistory().list()
+	cp.GenerationSeed = config.generationSeed(cp.Generation)
+	if config.ActivationFunctionCosts != nil {
+		cp.ActivationFunctionCosts = make(map[string]float64, len(config.ActivationFunctionCosts))
+		for afi, cost := range config.ActivationFunctionCosts {
+			cp.ActivationFunctionCosts[afi.Name()] = cost
+		}
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tempFilename := config.CheckpointFile + ".tmp"
+	if err := ioutil.WriteFile(tempFilename, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempFilename, config.CheckpointFile)
+}
+
+// ResumeFrom loads a checkpoint file that was written by an earlier evolution, where
+// config.CheckpointFile was set. The next call to Evolve or EvolveClasses will then continue
+// from where the checkpoint was written, instead of starting with a new population.
+// The same input data and configuration must be given, for the evolution to continue as if
+// it had not been interrupted. The activation function costs that were configured when the
+// checkpoint was written are restored into config.ActivationFunctionCosts.
+//
+// The state of the pseudo-random number generator is not saved. Instead, the generator is
+// seeded at the start of each generation, with a seed that is derived from the random seed
+// of the evolution, and the checkpoint stores the seed for the next generation. Resuming
+// depends on this re-seeding, which is not done if config.Rand was given by the caller.
+func (config *Config) ResumeFrom(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return err
+	}
+	if cp.Version < 1 || cp.Version > FormatVersion {
+		return fmt.Errorf("unsupported checkpoint format version: %d", cp.Version)
+	}
+	if len(cp.Population) == 0 || cp.BestNetwork == nil {
+		return errors.New("the checkpoint has no population")
+	}
+	if len(cp.Distances) != len(cp.Population) {
+		return errors.New("the checkpoint has no distances for some of the networks")
+	}
+	for i, net := range cp.Population {
+		if len(cp.Distances[i]) != len(net.AllNodes) {
+			return fmt.Errorf("the checkpoint has the wrong number of distances for network %d", i)
+		}
+		for j := range net.AllNodes {
+			net.AllNodes[j].distanceFromOutputNode = cp.Distances[i][j]
+		}
+		if err := config.checkActivationFunctions(net); err != nil {
+			return fmt.Errorf("network %d in the checkpoint: %s", i, err)
+		}
+	}
+	if cp.GenerationSeed != cp.Seed+int64(cp.Generation)+1 {
+		return fmt.Errorf("the checkpoint has the wrong random seed for generation %d", cp.Generation)
+	}
+	var costs map[ActivationFunctionIndex]float64
+	if cp.ActivationFunctionCosts != nil {
+		costs = make(map[ActivationFunctionIndex]float64, len(cp.ActivationFunctionCosts))
+		for name, cost := range cp.ActivationFunctionCosts {
+			afi, err := ActivationFunctionByName(name)
+			if err != nil {
+				return err
+			}
+			costs[afi] = cost
+		}
+	}
+	config.innovations = newInnovationHistory(cp.NextInnovation)
+	for _, split := range cp.Innovations {
+		config.innovations.splits[[2]int{split.Left, split.Right}] = split.Innovation
+	}
+	for role, innovation := range cp.InnovationNodes {
+		config.innovations.nodes[role] = innovation
+	}
+	if costs != nil {
+		config.ActivationFunctionCosts = costs
+	}
+	config.seed = cp.Seed
+	if config.Rand == nil {
+		config.Rand = rand.New(rand.NewSource(cp.GenerationSeed))
+		config.ownRand = true
+	}
+	config.initialized = true
+	config.resume = &cp
+	config.logger().Info("resuming evolution from checkpoint", "file", filename, "generation", cp.Generation, "seed", cp.Seed)
+	return nil
+}
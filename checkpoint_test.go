@@ -0,0 +1,116 @@
+package wann
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestResumeFrom(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 1.0, 0.0, 0.0},
+	}
+	multipliers := []float64{1.0, -1.0, -1.0, -1.0}
+	const checkpointFile = "test_checkpoint.json"
+	defer os.Remove(checkpointFile)
+
+	// Check both without and with speciation
+	for _, compatibilityThreshold := range []float64{0.0, 0.3} {
+		newConfig := func(generations int) *Config {
+			return &Config{
+				InitialConnectionRatio: 0.3,
+				Generations:            generations,
+				PopulationSize:         40,
+				RandomSeed:             commonSeed,
+				CompatibilityThreshold: compatibilityThreshold,
+				CrossoverRate:          0.25,
+			}
+		}
+		costs := map[ActivationFunctionIndex]float64{Sigmoid: 2.0, Swish: 3.0}
+
+		// Evolve for a few generations, and write a checkpoint at the end
+		interrupted := newConfig(5)
+		interrupted.CheckpointFile = checkpointFile
+		interrupted.ActivationFunctionCosts = costs
+		if _, err := interrupted.Evolve(inputData, multipliers); err != nil {
+			t.Fatal(err)
+		}
+
+		// Resume from the checkpoint, and evolve for a few more generations.
+		// Seed the pseudo-random number generator with something else first, as if this was a new process.
+		rand.Seed(1)
+		resumed := newConfig(10)
+		if err := resumed.ResumeFrom(checkpointFile); err != nil {
+			t.Fatal(err)
+		}
+		if len(resumed.ActivationFunctionCosts) != len(costs) || resumed.ActivationFunctionCosts[Swish] != costs[Swish] {
+			t.Errorf("expected the activation function costs to be restored, got %v", resumed.ActivationFunctionCosts)
+		}
+		resumedNetwork, err := resumed.Evolve(inputData, multipliers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Evolve without interruptions, using the same seed and activation function costs
+		uninterrupted := newConfig(10)
+		uninterrupted.ActivationFunctionCosts = costs
+		uninterrupted.Init()
+		uninterruptedNetwork, err := uninterrupted.Evolve(inputData, multipliers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		a, err := json.Marshal(resumedNetwork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(uninterruptedNetwork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(a) != string(b) {
+			t.Errorf("compatibility threshold %.1f: the resumed evolution gave a different network than the uninterrupted one:\n%s\n%s", compatibilityThreshold, a, b)
+		}
+	}
+}
+
+func TestCheckpointError(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            2,
+		PopulationSize:         10,
+		RandomSeed:             commonSeed,
+		CheckpointFile:         "this/directory/does/not/exist/checkpoint.json",
+	}
+	net, err := config.Evolve(inputData, []float64{1.0, -1.0})
+	if err == nil {
+		t.Error("expected an error when the checkpoint could not be written")
+	}
+	if net == nil {
+		t.Error("expected the best network so far, together with the error")
+	}
+}
+
+func TestJSONFloat(t *testing.T) {
+	for _, s := range []string{`1.5`, `"+Inf"`, `"-Inf"`, `"NaN"`} {
+		var f jsonFloat
+		if err := json.Unmarshal([]byte(s), &f); err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != s {
+			t.Errorf("expected %s, got %s", s, data)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package wann
+
+import (
+	"math"
+)
+
+// Argmax returns the index of the largest number in the given slice.
+// Returns -1 if the slice is empty.
+func Argmax(xs []float64) int {
+	if len(xs) == 0 {
+		return -1
+	}
+	maxIndex := 0
+	for i, x := range xs {
+		if x > xs[maxIndex] {
+			maxIndex = i
+		}
+	}
+	return maxIndex
+}
+
+// Softmax converts the given numbers to probabilities that sum up to 1
+func Softmax(xs []float64) []float64 {
+	probabilities := make([]float64, len(xs))
+	if len(xs) == 0 {
+		return probabilities
+	}
+	// Subtract the largest number before exponentiating, for numerical stability
+	largest := xs[Argmax(xs)]
+	sum := 0.0
+	for i, x := range xs {
+		probabilities[i] = math.Exp(x - largest)
+		sum += probabilities[i]
+	}
+	for i := range probabilities {
+		probabilities[i] /= sum
+	}
+	return probabilities
+}
+
+// Classify evaluates the network for the given input values and returns
+// the index of the output node with the largest value, which is the class label.
+func (net *Network) Classify(inputValues []float64) int {
+	return Argmax(net.EvaluateAll(inputValues))
+}
+
+// classMultipliers returns a slice of output multipliers for each class label.
+// The output node for the correct class is given a multiplier of 1,
+// while the other output nodes are given multipliers that sum up to -1.
+func classMultipliers(classLabels []int, outputs int) [][]float64 {
+	wrong := -1.0
+	if outputs > 1 {
+		wrong = -1.0 / float64(outputs-1)
+	}
+	multipliers := make([][]float64, len(classLabels))
+	for i, label := range classLabels {
+		multipliers[i] = make([]float64, outputs)
+		for j := range multipliers[i] {
+			if j == label {
+				multipliers[i][j] = 1.0
+			} else {
+				multipliers[i][j] = wrong
+			}
+		}
+	}
+	return multipliers
+}
@@ -0,0 +1,66 @@
+package wann
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func ExampleArgmax() {
+	fmt.Println(Argmax([]float64{0.1, 0.7, -2.0, 0.3}))
+	// Output:
+	// 1
+}
+
+func ExampleSoftmax() {
+	fmt.Printf("%.4f\n", Softmax([]float64{1.0, 2.0, 3.0}))
+	// Output:
+	// [0.0900 0.2447 0.6652]
+}
+
+func TestClassify(t *testing.T) {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 3,
+		Outputs:                3,
+		InitialConnectionRatio: 0.0,
+		sharedWeight:           1.0,
+	})
+	// Connect input node i to output node i, so that the largest input value wins
+	for i, outputNodeIndex := range net.OutputNodes {
+		net.AllNodes[outputNodeIndex].ActivationFunction = Linear
+		if err := net.AddConnection(net.InputNodes[i], outputNodeIndex); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if class := net.Classify([]float64{0.1, 0.2, 0.9}); class != 2 {
+		t.Errorf("expected class 2, got %d", class)
+	}
+	if class := net.Classify([]float64{0.8, 0.2, 0.1}); class != 0 {
+		t.Errorf("expected class 0, got %d", class)
+	}
+}
+
+func TestEvolveClasses(t *testing.T) {
+	inputData := [][]float64{
+		{1.0, 0.0, 0.0},
+		{0.0, 1.0, 0.0},
+		{0.0, 0.0, 1.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            2,
+		PopulationSize:         20,
+		RandomSeed:             commonSeed,
+	}
+	net, err := config.EvolveClasses(inputData, []int{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(net.OutputNodes) != 3 {
+		t.Errorf("expected one output node per class, got %d", len(net.OutputNodes))
+	}
+	if config.Outputs != 0 {
+		t.Errorf("expected config.Outputs to be left unchanged, got %d", config.Outputs)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/xyproto/wann"
+)
+
+func main() {
+	// Here are four shapes, representing: up, down, left and right:
+
+	up := []float64{
+		0.0, 1.0, 0.0, //  o
+		1.0, 1.0, 1.0} // ooo
+
+	down := []float64{
+		1.0, 1.0, 1.0, // ooo
+		0.0, 1.0, 0.0} //  o
+
+	left := []float64{
+		1.0, 1.0, 1.0, // ooo
+		0.0, 0.0, 1.0} //   o
+
+	right := []float64{
+		1.0, 1.0, 1.0, // ooo
+		1.0, 0.0, 0.0} // o
+
+	// Prepare the input data as a 2D slice
+	inputData := [][]float64{
+		up,
+		down,
+		left,
+		right,
+	}
+
+	// The class labels for: up, down, left, right
+	classLabels := []int{0, 1, 2, 3}
+	classNames := []string{"up", "down", "left", "right"}
+
+	// Prepare a neural network configuration struct
+	config := &wann.Config{
+		InitialConnectionRatio: 0.2,
+		Generations:            2000,
+		PopulationSize:         500,
+		Verbose:                true,
+	}
+
+	// Evolve a network with one output node per class
+	trainedNetwork, err := config.EvolveClasses(inputData, classLabels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Now classify each of the shapes, using the trained network
+	correct := 0
+	for i, input := range inputData {
+		class := trainedNetwork.Classify(input)
+		fmt.Printf("%s is classified as %s\n", classNames[i], classNames[class])
+		if class == classLabels[i] {
+			correct++
+		}
+	}
+	fmt.Printf("%d of %d shapes were classified correctly\n", correct, len(inputData))
+
+	// Save the trained network as an SVG image
+	if config.Verbose {
+		fmt.Print("Writing network.svg...")
+	}
+	if err := trainedNetwork.WriteSVG("network.svg"); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+	if config.Verbose {
+		fmt.Println("ok")
+	}
+}
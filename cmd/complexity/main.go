@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/xyproto/wann"
+)
+
+// Benchmark the activation functions and output a complexity table that can replace wann.DefaultComplexity
+func main() {
+	fmt.Print(wann.ComplexityTable(wann.BenchmarkComplexity()))
+}
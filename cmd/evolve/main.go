@@ -77,4 +77,16 @@ func main() {
 	if config.Verbose {
 		fmt.Println("ok")
 	}
+
+	// Save the trained network as a JSON file, that can be loaded again with wann.Load
+	if config.Verbose {
+		fmt.Print("Writing network.json...")
+	}
+	if err := trainedNetwork.Save("network.json"); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+	if config.Verbose {
+		fmt.Println("ok")
+	}
 }
@@ -0,0 +1,181 @@
+package wann
+
+import (
+	"fmt"
+)
+
+// stepKind is the kind of calculation that a step in a compiled network performs
+type stepKind int
+
+const (
+	// stepInput uses one of the input values
+	stepInput stepKind = iota
+	// stepConstant uses a fixed value
+	stepConstant
+	// stepActivation runs the weighted sum of earlier steps through an activation function
+	stepActivation
+)
+
+// compiledStep is one calculation in a compiled network. The result is stored at the same index as the step.
+type compiledStep struct {
+	kind        stepKind
+	input       int                   // The index of the input value, for stepInput
+	constant    float64               // The value, for stepConstant
+	f           func(float64) float64 // The activation function, for stepActivation
+	first, last int                   // The range of operands, for stepActivation
+}
+
+// CompiledNetwork is a flat evaluation plan for a network, made by Network.Compile.
+// It can be evaluated many times without any recursion or memory allocations.
+// A CompiledNetwork is not safe for concurrent use, since it reuses a buffer for the results of each step.
+type CompiledNetwork struct {
+	net      *Network
+	inputs   int            // The number of input values that the plan is made for
+	steps    []compiledStep // One step per connected node, in topological order
+	operands []int          // The steps that each activation step sums up
+	outputs  []int          // The step with the result for each output node, or -1 for 0.0
+	ends     []int          // The index after the last step that each output node depends on
+	values   []float64      // The result of each step
+}
+
+// Compile creates a flat evaluation plan for the network, for evaluating it many times.
+// The nodes that are connected to the output nodes are sorted topologically, and each node
+// gets one step, so that the plan grows linearly with the size of the network.
+//
+// The nodes are evaluated in the same order as for Evaluate and EvaluateAll, which gives exactly the same results,
+// and as for the first Step after Reset. For recurrent networks, the connections that close a cycle use 0.
+//
+// The network must be compiled again if it is modified, but the shared weight is read when evaluating.
+func (net *Network) Compile() *CompiledNetwork {
+	c := &CompiledNetwork{
+		net:    net,
+		inputs: len(net.InputNodes),
+	}
+	inputPositions := make(map[NeuronIndex]int, len(net.InputNodes))
+	for i, inputNodeIndex := range net.InputNodes {
+		inputPositions[inputNodeIndex] = i
+	}
+	// The step for each node, and if it is unvisited, being visited or visited, see Step
+	nodeSteps := make([]int, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	for _, outputNodeIndex := range net.Outputs() {
+		step := c.compileNode(outputNodeIndex, inputPositions, nodeSteps, states)
+		c.outputs = append(c.outputs, step)
+		c.ends = append(c.ends, len(c.steps))
+	}
+	c.values = make([]float64, len(c.steps))
+	return c
+}
+
+// compileNode adds the step for evaluating the given node, after the steps for its input nodes,
+// unless the node already has a step. It returns the step with the result, or -1 if the result is 0.0.
+func (c *CompiledNetwork) compileNode(nodeIndex NeuronIndex, inputPositions map[NeuronIndex]int, nodeSteps, states []int) int {
+	if states[nodeIndex] == nodeVisited {
+		return nodeSteps[nodeIndex]
+	}
+	states[nodeIndex] = nodeVisiting
+	neuron := &c.net.AllNodes[nodeIndex]
+	operands := make([]int, 0, len(neuron.InputNodes))
+	counter := 0
+	for _, inputNeuronIndex := range neuron.InputNodes {
+		if int(inputNeuronIndex) >= len(c.net.AllNodes) {
+			continue
+		}
+		counter++
+		if states[inputNeuronIndex] == nodeVisiting {
+			// This connection closes a cycle, and uses 0.0
+			continue
+		}
+		if step := c.compileNode(inputNeuronIndex, inputPositions, nodeSteps, states); step >= 0 {
+			operands = append(operands, step)
+		}
+	}
+	states[nodeIndex] = nodeVisited
+	nodeSteps[nodeIndex] = -1
+	// No input neurons. Use the input value or the .Value field if this is not the output node.
+	// The .Value field of the input nodes is always set when evaluating.
+	if counter == 0 && !neuron.IsOutput() {
+		if i, ok := inputPositions[nodeIndex]; ok {
+			nodeSteps[nodeIndex] = c.addStep(compiledStep{kind: stepInput, input: i})
+		} else if neuron.Value != nil {
+			nodeSteps[nodeIndex] = c.addStep(compiledStep{kind: stepConstant, constant: *neuron.Value})
+		}
+		return nodeSteps[nodeIndex]
+	}
+	if counter == 0 {
+		return -1
+	}
+	first := len(c.operands)
+	c.operands = append(c.operands, operands...)
+	nodeSteps[nodeIndex] = c.addStep(compiledStep{kind: stepActivation, f: neuron.GetActivationFunction(), first: first, last: len(c.operands)})
+	return nodeSteps[nodeIndex]
+}
+
+// addStep adds a step to the plan, and returns its index
+func (c *CompiledNetwork) addStep(step compiledStep) int {
+	c.steps = append(c.steps, step)
+	return len(c.steps) - 1
+}
+
+// run performs the steps up to the given index, for the given input values and shared weight,
+// and stores the result of each step in values
+func (c *CompiledNetwork) run(values []float64, end int, inputValues []float64, weight float64) {
+	for i, step := range c.steps[:end] {
+		switch step.kind {
+		case stepInput:
+			values[i] = inputValues[step.input]
+		case stepConstant:
+			values[i] = step.constant
+		case stepActivation:
+			summed := 0.0
+			for _, operand := range c.operands[step.first:step.last] {
+				summed += values[operand] * weight
+			}
+			values[i] = step.f(summed)
+		}
+	}
+}
+
+// result returns the result for the given output node, given the result of each step
+func (c *CompiledNetwork) result(values []float64, output int) float64 {
+	if c.outputs[output] < 0 {
+		return 0.0
+	}
+	return values[c.outputs[output]]
+}
+
+// checkInputValues returns an error if the number of input values differs from the number of input nodes.
+// Compiled networks and models need exactly one value per input node.
+func (c *CompiledNetwork) checkInputValues(inputValues []float64) error {
+	if len(inputValues) != c.inputs {
+		return fmt.Errorf("expected %d input values, got %d", c.inputs, len(inputValues))
+	}
+	return nil
+}
+
+// Evaluate returns the value of the first output node, which is the same as for Network.Evaluate.
+// Returns an error if there is not exactly one input value per input node.
+func (c *CompiledNetwork) Evaluate(inputValues []float64) (float64, error) {
+	if err := c.checkInputValues(inputValues); err != nil {
+		return 0.0, err
+	}
+	c.run(c.values, c.ends[0], inputValues, c.net.Weight)
+	return c.result(c.values, 0), nil
+}
+
+// EvaluateAll evaluates the network for the given input values, and stores one result
+// per output node in results, which is returned. A new slice is only allocated if results is too short.
+// Returns an error if there is not exactly one input value per input node.
+func (c *CompiledNetwork) EvaluateAll(inputValues []float64, results []float64) ([]float64, error) {
+	if err := c.checkInputValues(inputValues); err != nil {
+		return nil, err
+	}
+	if len(results) < len(c.outputs) {
+		results = make([]float64, len(c.outputs))
+	}
+	c.run(c.values, len(c.steps), inputValues, c.net.Weight)
+	for i := range c.outputs {
+		results[i] = c.result(c.values, i)
+	}
+	return results[:len(c.outputs)], nil
+}
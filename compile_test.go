@@ -0,0 +1,133 @@
+package wann
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	config := &Config{
+		inputs:                 6,
+		Outputs:                3,
+		InitialConnectionRatio: 0.5,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{-0.5, 0.25, 1.0, 0.75, 0.0, -1.0},
+	}
+	for n := 0; n < 20; n++ {
+		net := NewNetwork(config)
+		net.UpdateNetworkPointers()
+		for i := 0; i < n; i++ {
+			net.ModifyWith(MutationRates{InsertNode: 1.0, AddConnection: 1.0, ChangeActivationFunction: 1.0, RemoveConnection: 0.3, RemoveNode: 0.3}, 10)
+		}
+		compiled := net.Compile()
+		if len(compiled.steps) > len(net.Connected()) {
+			t.Fatalf("network %d: expected at most one step per connected node, got %d steps for %d nodes", n, len(compiled.steps), len(net.Connected()))
+		}
+		var (
+			results []float64
+			err     error
+		)
+		for _, weight := range DefaultWeightSamples {
+			net.SetWeight(weight)
+			for _, inputValues := range inputData {
+				// The first time step after Reset evaluates every node fully
+				net.Reset()
+				expected := net.Step(inputValues)
+				if results, err = compiled.EvaluateAll(inputValues, results); err != nil {
+					t.Fatal(err)
+				}
+				for i := range expected {
+					if expected[i] != results[i] {
+						t.Fatalf("network %d, weight %f: expected %v, got %v", n, weight, expected, results)
+					}
+				}
+				result, err := compiled.Evaluate(inputValues)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if a := net.Evaluate(inputValues); a != result || a != expected[0] {
+					t.Fatalf("network %d, weight %f: expected %v, got %v", n, weight, a, result)
+				}
+				evaluated := net.EvaluateAll(inputValues)
+				for i := range evaluated {
+					if evaluated[i] != results[i] {
+						t.Fatalf("network %d, weight %f: expected %v, got %v", n, weight, evaluated, results)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCompileSharedNodes(t *testing.T) {
+	// A ladder where each node is used twice by the next one, which gives 2^n paths from the output node
+	net := NewNetwork(&Config{inputs: 1})
+	net.UpdateNetworkPointers()
+	net.AllNodes[net.OutputNode].ActivationFunction = Linear
+	previous := net.InputNodes[0]
+	for i := 0; i < 30; i++ {
+		_, a := net.NewNeuron()
+		_, b := net.NewNeuron()
+		net.AllNodes[a].ActivationFunction, net.AllNodes[b].ActivationFunction = Linear, Linear
+		net.AllNodes[a].InputNodes = []NeuronIndex{previous}
+		net.AllNodes[b].InputNodes = []NeuronIndex{previous, a}
+		previous = b
+	}
+	net.AllNodes[net.OutputNode].InputNodes = []NeuronIndex{previous}
+	net.UpdateNetworkPointers()
+	compiled := net.Compile()
+	if len(compiled.steps) != len(net.AllNodes) {
+		t.Errorf("expected one step per node, got %d steps for %d nodes", len(compiled.steps), len(net.AllNodes))
+	}
+	// Each node doubles the value, with a weight of 1.0
+	net.SetWeight(1.0)
+	if result, err := compiled.Evaluate([]float64{1.0}); err != nil || result != math.Pow(2, 30) {
+		t.Errorf("expected %v, got %v", math.Pow(2, 30), result)
+	}
+	// Evaluate also visits each node once
+	if result := net.Evaluate([]float64{1.0}); result != math.Pow(2, 30) {
+		t.Errorf("expected %v from Evaluate, got %v", math.Pow(2, 30), result)
+	}
+}
+
+func TestCompileAllocations(t *testing.T) {
+	config := &Config{
+		inputs:                 6,
+		Outputs:                2,
+		InitialConnectionRatio: 0.7,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for i := 0; i < 20; i++ {
+		net.Modify(10)
+	}
+	compiled := net.Compile()
+	inputValues := []float64{0.0, 1.0, 0.0, 1.0, 1.0, 1.0}
+	results := make([]float64, 2)
+	allocations := testing.AllocsPerRun(100, func() {
+		compiled.Evaluate(inputValues)
+		compiled.EvaluateAll(inputValues, results)
+	})
+	if allocations != 0 {
+		t.Errorf("expected no allocations, got %f", allocations)
+	}
+	// There must be one input value per input node
+	for _, wrong := range [][]float64{inputValues[:5], append(inputValues, 1.0)} {
+		if _, err := compiled.Evaluate(wrong); err == nil {
+			t.Errorf("expected an error for %d input values", len(wrong))
+		}
+		if _, err := compiled.EvaluateAll(wrong, results); err == nil {
+			t.Errorf("expected an error for %d input values", len(wrong))
+		}
+		if _, err := compiled.EvaluateBatch([][]float64{inputValues, wrong}); err == nil {
+			t.Errorf("expected an error for a row with %d input values", len(wrong))
+		}
+	}
+}
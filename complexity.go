@@ -0,0 +1,110 @@
+package wann
+
+// ComplexityFunc measures how complex a network is. The built-in fitness functions divide the score by the
+// complexity, so that less complex networks are preferred. It must return a number larger than 0,
+// and 1.0 means that there is no penalty.
+type ComplexityFunc func(net *Network) float64
+
+// ComplexityWeights are the multipliers for each part of the default complexity measure, see WeightedComplexity
+type ComplexityWeights struct {
+	// How much should the complexity of the activation functions matter, in relation to the number of connected nodes?
+	ActivationFunctions float64
+	// Weight the number of connected nodes
+	ConnectedNodes float64
+	// Weight the number of input nodes that are connected to the output nodes
+	OutputNodeInputNodes float64
+}
+
+// DefaultComplexityWeights are the complexity weights that are used if Config.ComplexityWeights is not set
+var DefaultComplexityWeights = ComplexityWeights{ActivationFunctions: 1.0, ConnectedNodes: 2.0, OutputNodeInputNodes: 3.0}
+
+// WeightedComplexity returns a complexity function that sums up the estimated complexity of all the
+// activation functions (except for the input nodes), the number of connected nodes and the number of
+// input nodes to the output nodes, using the given weights. This penalizes both slow activation functions
+// and unconnected nodes. The returned complexity is 1.0 at a minimum.
+func WeightedComplexity(weights ComplexityWeights) ComplexityFunc {
+	return func(net *Network) float64 {
+		return net.weightedComplexity(weights, nil)
+	}
+}
+
+// weightedComplexity measures the network complexity, using the given weights and the given complexity
+// of each activation function. ComplexityEstimate is used for the activation functions that are not in costs.
+func (net *Network) weightedComplexity(weights ComplexityWeights, costs map[ActivationFunctionIndex]float64) float64 {
+	activationFunctionComplexity := 0.0
+	// Sum the complexity of all activation functions, except for the input nodes and the bias node,
+	// since their activation functions are never used. This penalizes both slow activation functions and
+	// unconnected nodes.
+	isInput := make([]bool, len(net.AllNodes))
+	for _, inputNodeIndex := range net.InputNodes {
+		isInput[inputNodeIndex] = true
+	}
+	for i, n := range net.AllNodes {
+		if isInput[i] || net.IsBias(NeuronIndex(i)) {
+			continue
+		}
+		if cost, ok := costs[n.ActivationFunction]; ok {
+			activationFunctionComplexity += cost
+		} else {
+			activationFunctionComplexity += complexityEstimate(n.ActivationFunction)
+		}
+	}
+	activationFunctionComplexity *= weights.ActivationFunctions
+	// The number of connected nodes should also carry some weight
+	connectedNodes := float64(len(net.Connected())) * weights.ConnectedNodes
+	// The number of input nodes to the output nodes
+	outputNodeInputNodes := 0
+	for _, outputNodeIndex := range net.Outputs() {
+		outputNodeInputNodes += len(net.AllNodes[outputNodeIndex].InputNodes)
+	}
+	outputNodeComplexity := float64(outputNodeInputNodes) * weights.OutputNodeInputNodes
+	// This must always be larger than 0, to avoid divide by zero later
+	return connectedNodes + activationFunctionComplexity + outputNodeComplexity + 1.0
+}
+
+// ConnectionComplexity is 1.0 plus the number of connections in the network
+func ConnectionComplexity(net *Network) float64 {
+	connections := 0
+	for _, node := range net.AllNodes {
+		connections += len(node.InputNodes)
+	}
+	return float64(connections) + 1.0
+}
+
+// DepthComplexity is 1.0 plus the depth of the network, see Network.Depth
+func DepthComplexity(net *Network) float64 {
+	return float64(net.Depth()) + 1.0
+}
+
+// NoComplexity is always 1.0, so that the complexity of a network does not affect its score
+func NoComplexity(net *Network) float64 {
+	return 1.0
+}
+
+// SetComplexity sets the function that is used for measuring the complexity of this network.
+// WeightedComplexity(DefaultComplexityWeights) is used if f is nil.
+func (net *Network) SetComplexity(f ComplexityFunc) {
+	net.complexity = f
+}
+
+// complexity returns the configured complexity function, or the weighted complexity with the configured weights and costs.
+// The number of connections is a separate objective when config.MultiObjective is set, so then there is no
+// complexity penalty by default.
+func (config *Config) complexity() ComplexityFunc {
+	switch {
+	case config.Complexity != nil:
+		return config.Complexity
+	case config.ComplexityWeights != (ComplexityWeights{}) || config.ActivationFunctionCosts != nil:
+		weights, costs := config.ComplexityWeights, config.ActivationFunctionCosts
+		if weights == (ComplexityWeights{}) {
+			weights = DefaultComplexityWeights
+		}
+		return func(net *Network) float64 {
+			return net.weightedComplexity(weights, costs)
+		}
+	case config.MultiObjective:
+		return NoComplexity
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package wann
+
+import (
+	"testing"
+)
+
+func TestComplexityFunctions(t *testing.T) {
+	net := newIdentityNetwork(3)
+	if c := NoComplexity(net); c != 1.0 {
+		t.Errorf("expected no complexity penalty, got %f", c)
+	}
+	if c := ConnectionComplexity(net); c != 4.0 {
+		t.Errorf("expected a complexity of 1 + 3 connections, got %f", c)
+	}
+	if c := DepthComplexity(net); c < 2.0 {
+		t.Errorf("expected a complexity of at least 1 + a depth of 1, got %f", c)
+	}
+	if a, b := net.Complexity(), WeightedComplexity(DefaultComplexityWeights)(net); a != b {
+		t.Errorf("expected the default weights to be used by default, got %f and %f", a, b)
+	}
+	// Only count the connected nodes
+	nodesOnly := WeightedComplexity(ComplexityWeights{ConnectedNodes: 1.0})(net)
+	if nodesOnly != float64(len(net.Connected()))+1.0 {
+		t.Errorf("expected a complexity of 1 + %d connected nodes, got %f", len(net.Connected()), nodesOnly)
+	}
+	net.SetComplexity(ConnectionComplexity)
+	inputData := [][]float64{{1.0, 0.0, 0.0}, {0.0, 1.0, 0.0}}
+	fitness, err := AccuracyFitness(inputData, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score := fitness(net); score != 1.0/4.0 {
+		t.Errorf("expected the score to be divided by the configured complexity, got %f", score)
+	}
+	if c := net.Copy().Complexity(); c != 4.0 {
+		t.Errorf("expected a copy to use the same complexity function, got %f", c)
+	}
+}
+
+func TestActivationFunctionCosts(t *testing.T) {
+	net := newIdentityNetwork(2)
+	config := &Config{
+		ActivationFunctionCosts: map[ActivationFunctionIndex]float64{Linear: 10.0},
+	}
+	net.SetComplexity(config.complexity())
+	// The two output nodes are linear, and both are connected to an input node
+	expected := 2*10.0 + float64(len(net.Connected()))*DefaultComplexityWeights.ConnectedNodes + 2*DefaultComplexityWeights.OutputNodeInputNodes + 1.0
+	if c := net.Complexity(); c != expected {
+		t.Errorf("expected a complexity of %f, got %f", expected, c)
+	}
+}
+
+func TestEvolveComplexity(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         40,
+		RandomSeed:             commonSeed,
+		Complexity:             NoComplexity,
+	}
+	net, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := net.Complexity(); c != 1.0 {
+		t.Errorf("expected the evolved network to use the configured complexity function, got %f", c)
+	}
+}
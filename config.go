@@ -1,8 +1,9 @@
 package wann
 
 import (
-	"fmt"
+	"log/slog"
 	"math/rand"
+	"runtime"
 	"time"
 )
 
@@ -12,8 +13,13 @@ import (
 type Config struct {
 	// Number of input neurons (inputs per slice of floats in inputData in the Evolve function)
 	inputs int
+	// Number of output neurons. A single output neuron is used if this is 0.
+	Outputs int
 	// When initializing a network, this is the propability that the node will be connected to the output node
 	InitialConnectionRatio float64
+	// Add a bias node to each network, which always has the value 1, so that the activation functions can be shifted.
+	// It is connected to the output nodes in the same way as the input nodes, and is not given any input value.
+	Bias bool
 	// sharedWeight is the weight that is shared by all nodes, since this is a Weight Agnostic Neural Network
 	sharedWeight float64
 	// How many generations to train for, at a maximum?
@@ -22,12 +28,97 @@ type Config struct {
 	PopulationSize int
 	// For how many generations should the training go on, without any improvement in the best score? Disabled if 0.
 	MaxIterationsWithoutBestImprovement int
+	// For how long should the training go on, at a maximum? Checked after each generation,
+	// and while finding the best weight for the best network at the end. Disabled if 0.
+	TimeBudget time.Duration
+	// Stop training when the best score is at least this good, if UseTargetScore is set
+	TargetScore float64
+	// Use TargetScore? This makes it possible to use any target score, also 0 or negative ones.
+	UseTargetScore bool
+	// Fitness is used for scoring each network when evolving, instead of the
+	// built-in fitness functions that Evolve and EvolveClasses use by default.
+	Fitness FitnessFunc
+	// The shared weights that every network is scored with, for each generation.
+	// A single random weight is used per generation if this is empty. See also DefaultWeightSamples.
+	WeightSamples []float64
+	// How the scores for each of the WeightSamples are combined into one score per network
+	WeightAggregation Aggregation
+	// Complexity measures how complex each network is, when using the built-in fitness functions, which divide the
+	// score by the complexity. WeightedComplexity with the ComplexityWeights is used if this is nil.
+	// See also ConnectionComplexity, DepthComplexity and NoComplexity.
+	Complexity ComplexityFunc
+	// The weights that are used for measuring the complexity, if Complexity is nil. DefaultComplexityWeights is used if this is not set.
+	ComplexityWeights ComplexityWeights
+	// The complexity of each activation function, used for measuring the complexity if Complexity is nil.
+	// ComplexityEstimate is used for the activation functions that are not in this map.
+	ActivationFunctionCosts map[ActivationFunctionIndex]float64
+	// Rank the networks by three separate objectives, as in NSGA-II, instead of by the score alone: the score
+	// (aggregated over WeightSamples), the best score for any of the shared weights and the number of connections.
+	// The scores are not divided by the complexity, unless Complexity, ComplexityWeights or ActivationFunctionCosts is set.
+	// The networks in the first Pareto front of the last generation can be retrieved with ParetoFront.
+	// Can not be combined with speciation.
+	MultiObjective bool
+	// The activation functions that may be chosen for new nodes and when modifying networks. All of the
+	// ActivationFunctions may be chosen if this is empty. Networks that are loaded with LoadNetwork or
+	// ResumeFrom may only use these activation functions.
+	AllowedActivationFunctions []ActivationFunctionIndex
+	// Networks with a compatibility distance below this are placed in the same species, and mainly compete
+	// within their species, which protects new topologies. Speciation is disabled if 0. See CompatibilityDistance.
+	CompatibilityThreshold float64
+	// The relative probabilities of each way of modifying a network. If this is not set, nodes are inserted,
+	// connections are added and activation functions are changed with equal probability. See MutationRates.
+	MutationRates MutationRates
+	// How many times each new network is modified. It is modified once if this is 0.
+	MutationsPerOffspring int
+	// Selector decides which networks are kept and which ones get offspring, for each generation.
+	// The best 7% are kept and used as parents if this is nil. See TruncationSelection,
+	// TournamentSelection and RankSelection.
+	Selector Selector
+	// The probability that a new network is created by crossing over two good networks, before it is modified.
+	// Otherwise, a good network is copied and modified. See Crossover.
+	CrossoverRate float64
+	// Allow new connections that form cycles, for networks that keep a hidden state between time steps.
+	// The fitness function should then use Network.Reset and Network.Step. See Network.Recurrent.
+	Recurrent bool
+	// Number of goroutines to use when scoring a population. runtime.NumCPU() is used if this is 0.
+	// The results are the same for a given RandomSeed, regardless of the number of workers.
+	Workers int
+	// Rand is the pseudo-random number generator that is used when evolving networks.
+	// Init creates one, using the random seed, if this is nil, and that one is re-seeded for each generation.
+	// A generator that is given here is used as it is, and is never seeded, so RandomSeed is not used
+	// and an evolution that is resumed from a checkpoint will not give the same results as an uninterrupted one.
+	Rand *rand.Rand
 	// RandomSeed, for initializing the random number generator. The current time is used for the seed if this is set to 0.
 	RandomSeed int64
-	// Verbose output
+	// OnGeneration is called with statistics about each generation, after it has been scored
+	OnGeneration func(stats GenerationStats)
+	// Logger is used for all diagnostic messages, like the random seed and the statistics for each generation.
+	// Nothing is logged if this is nil, unless Verbose is set.
+	Logger *slog.Logger
+	// Verbose output to stdout, if no Logger is set
 	Verbose bool
-	// Has the pseudo-random number generator been seeded and the activation function complexity been estimated yet?
+	// A checkpoint with the state of the evolution is written to this file, if it is set. See ResumeFrom.
+	CheckpointFile string
+	// How many generations between each checkpoint. A checkpoint is written for every generation if this is 0.
+	CheckpointInterval int
+	// The number of classes, when evolving with EvolveClasses, which is the least number of output nodes to use
+	classes int
+	// Has the pseudo-random number generator been seeded yet?
 	initialized bool
+	// Was config.Rand created by Init or ResumeFrom, and not given by the caller?
+	ownRand bool
+	// The logger that writes to stdout, if Verbose is set and Logger is nil, see Init
+	verboseLogger *slog.Logger
+	// The random seed that is actually used, which is based on the time if RandomSeed is 0
+	seed int64
+	// Shared by all networks that are created with this configuration, see Crossover
+	innovations *innovationHistory
+	// The first Pareto front of the last generation, if MultiObjective is set
+	paretoFront []*Network
+	// The score of the returned network for each of the weight samples, see WeightScores
+	weightScores []float64
+	// The checkpoint to resume evolving from, if any
+	resume *checkpoint
 }
 
 // initialize the pseaudo-random number generator, either using the config.RandomSeed or the time
@@ -36,16 +127,50 @@ func (config *Config) initRandom() {
 	if config.RandomSeed == 0 {
 		randomSeed = time.Now().UTC().UnixNano()
 	}
-	if config.Verbose {
-		fmt.Println("Using random seed:", randomSeed)
+	config.seed = randomSeed
+	if config.Rand != nil && !config.ownRand {
+		// Use the given pseudo-random number generator as it is
+		config.logger().Info("using the given pseudo-random number generator")
+		return
 	}
+	config.logger().Info("using random seed", "seed", randomSeed)
 	// Initialize the pseudo-random number generator
-	rand.Seed(randomSeed)
+	if config.Rand == nil {
+		config.Rand = rand.New(rand.NewSource(randomSeed))
+		config.ownRand = true
+	} else {
+		config.Rand.Seed(randomSeed)
+	}
+}
+
+// generationSeed returns the seed for the pseudo-random number generator, at the start of the given generation
+func (config *Config) generationSeed(generation int) int64 {
+	return config.seed + int64(generation) + 1
+}
+
+// outputs returns the number of output neurons to use, which is at least 1, and at least one per class
+// when evolving with EvolveClasses
+func (config *Config) outputs() int {
+	if config.Outputs < config.classes {
+		return config.classes
+	}
+	if config.Outputs < 1 {
+		return 1
+	}
+	return config.Outputs
+}
+
+// workers returns the number of goroutines to use when scoring a population
+func (config *Config) workers() int {
+	if config.Workers < 1 {
+		return runtime.NumCPU()
+	}
+	return config.Workers
 }
 
-// Init will initialize the pseudo-random number generator and estimate the complexity of the available activation functions
+// Init will initialize the pseudo-random number generator, and the logger if config.Verbose is set
 func (config *Config) Init() {
+	config.initLogger()
 	config.initRandom()
-	config.estimateComplexity()
 	config.initialized = true
 }
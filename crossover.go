@@ -0,0 +1,80 @@
+package wann
+
+// Crossover combines two networks into a new network, by aligning their nodes by innovation number, as in NEAT.
+// The first network should be the fittest one, since the new network gets all the nodes and connections of a.
+// The nodes that both networks have get the activation function of a randomly chosen parent, and the connections
+// that b has between such nodes are also added, as long as they do not create a cycle, unless a is recurrent.
+func Crossover(a, b *Network) *Network {
+	child := a.Copy()
+
+	// Find the nodes of the new network by innovation number
+	byInnovation := make(map[int]NeuronIndex, len(child.AllNodes))
+	for i, node := range child.AllNodes {
+		byInnovation[node.Innovation] = NeuronIndex(i)
+	}
+
+	// Inherit the activation functions of the matching nodes from a random parent
+	for _, node := range b.AllNodes {
+		if ni, ok := byInnovation[node.Innovation]; ok && child.random().Intn(2) == 1 {
+			child.AllNodes[ni].ActivationFunction = node.ActivationFunction
+		}
+	}
+
+	// Add the connections from b, between nodes that both networks have
+	for _, node := range b.AllNodes {
+		to, ok := byInnovation[node.Innovation]
+		if !ok || child.IsInput(to) {
+			continue
+		}
+		for _, inputNodeIndex := range node.InputNodes {
+			from, ok := byInnovation[b.AllNodes[inputNodeIndex].Innovation]
+			if !ok || from == to || child.AllNodes[to].HasInput(from) || (!child.Recurrent && (child.IsOutput(from) || child.dependsOn(from, to))) {
+				continue
+			}
+			if err := child.AllNodes[to].AddInput(from); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return child
+}
+
+// dependsOn checks if the output of node a depends on node b, by following the input nodes of a
+func (net *Network) dependsOn(a, b NeuronIndex) bool {
+	visited := make([]bool, len(net.AllNodes))
+	stack := []NeuronIndex{a}
+	for len(stack) > 0 {
+		ni := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if ni == b {
+			return true
+		}
+		if visited[ni] {
+			continue
+		}
+		visited[ni] = true
+		stack = append(stack, net.AllNodes[ni].InputNodes...)
+	}
+	return false
+}
+
+// offspring creates a new network from the given parents, which must be sorted by score, in descending order.
+// The parent is chosen by pick, which returns an index into parents. It is copied, or crossed over with
+// another parent if config.CrossoverRate allows it, and the new network is then modified, see config.MutationRates.
+func (config *Config) offspring(parents []*Network, pick func() int, maxModificationIterations int) *Network {
+	i := pick()
+	var child *Network
+	if len(parents) > 1 && config.CrossoverRate > 0 && config.Rand.Float64() < config.CrossoverRate {
+		j := pick()
+		if j < i {
+			// The fittest parent goes first
+			i, j = j, i
+		}
+		child = Crossover(parents[i], parents[j])
+	} else {
+		child = parents[i].Copy()
+	}
+	config.modify(child, maxModificationIterations)
+	return child
+}
@@ -0,0 +1,115 @@
+package wann
+
+import (
+	"testing"
+)
+
+func TestCrossover(t *testing.T) {
+	config := &Config{
+		inputs:                 5,
+		InitialConnectionRatio: 0.5,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+
+	// Two lineages that share the same ancestor
+	a, b := net.Copy(), net.Copy()
+	for i := 0; i < 5; i++ {
+		a.Modify(10)
+		b.Modify(10)
+	}
+
+	child := Crossover(a, b)
+	if len(child.AllNodes) != len(a.AllNodes) {
+		t.Errorf("expected the child to have the %d nodes of the first parent, got %d", len(a.AllNodes), len(child.AllNodes))
+	}
+	for i, node := range a.AllNodes {
+		for _, inputNodeIndex := range node.InputNodes {
+			if !child.AllNodes[i].HasInput(inputNodeIndex) {
+				t.Errorf("expected the child to have the connection from node %d to node %d", inputNodeIndex, i)
+			}
+		}
+	}
+	for _, node := range child.AllNodes {
+		if node.Net != child {
+			t.Fatal("the .Net pointer of a node does not point to the new network")
+		}
+	}
+	// Evaluating the network would never return if a cycle had been created
+	child.Evaluate([]float64{0.1, 0.2, 0.3, 0.4, 0.5})
+
+	// Nodes that are inserted between the same two nodes get the same innovation number
+	c := net.Copy()
+	d := net.Copy()
+	for i, node := range c.AllNodes {
+		if len(node.InputNodes) > 0 {
+			innovation := c.newInnovation(node.InputNodes[0], NeuronIndex(i))
+			if innovation != d.newInnovation(node.InputNodes[0], NeuronIndex(i)) {
+				t.Error("expected the same innovation number for the same inserted node in two networks")
+			}
+			break
+		}
+	}
+}
+
+func TestEvolveCrossover(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         40,
+		RandomSeed:             commonSeed,
+		CrossoverRate:          0.5,
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInnovationNumbers(t *testing.T) {
+	config := &Config{
+		inputs:                 4,
+		Outputs:                2,
+		InitialConnectionRatio: 0.5,
+		RandomSeed:             commonSeed,
+		Bias:                   true,
+	}
+	config.initRandom()
+	a, b := NewNetwork(config), NewNetwork(config)
+	a.UpdateNetworkPointers()
+	b.UpdateNetworkPointers()
+
+	// The input, output and bias nodes get the same innovation numbers in all networks of a configuration
+	for i := range a.AllNodes {
+		if a.AllNodes[i].Innovation != b.AllNodes[i].Innovation {
+			t.Errorf("expected node %d to have the same innovation number in both networks", i)
+		}
+	}
+
+	// Inserted nodes and other new nodes never get the innovation number of another node
+	for i := 0; i < 10; i++ {
+		a.InsertRandomNode()
+	}
+	a.NewNeuron()
+	if err := b.NewInputNode(Linear, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, net := range []*Network{&a, &b} {
+		seen := make(map[int]bool, len(net.AllNodes))
+		for i, node := range net.AllNodes {
+			if seen[node.Innovation] {
+				t.Errorf("node %d has an innovation number that is already used: %d", i, node.Innovation)
+			}
+			seen[node.Innovation] = true
+		}
+	}
+	if a.AllNodes[len(a.AllNodes)-1].Innovation == b.AllNodes[len(b.AllNodes)-1].Innovation {
+		t.Error("expected two new nodes to get different innovation numbers")
+	}
+}
@@ -23,11 +23,23 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 		d              = float64(net.Depth()) * 2.5
 		width          = marginLeft + int(float64(nodeRadius)*2.0*d) + betweenPadding*(int(d)-1) + nodeRadius + marginRight
 		l              = float64(len(net.InputNodes))
+		outputs        = net.Outputs()
 		height         = marginTop + int(float64(nodeRadius)*1.5*l) + betweenPadding*(int(l)-1) + marginBottom
 		imgPadding     = 5
 		lineWidth      = 2
 	)
 
+	// Make room for the bias node
+	if net.Bias {
+		l++
+		height = marginTop + int(float64(nodeRadius)*1.5*l) + betweenPadding*(int(l)-1) + marginBottom
+	}
+
+	if m := float64(len(outputs)) * 1.6; m > l {
+		// Make room for all the output nodes, and their labels
+		height = marginTop + int(float64(nodeRadius)*1.5*m) + betweenPadding*(int(m)-1) + marginBottom
+	}
+
 	if width < 128 {
 		width = 128
 	}
@@ -44,9 +56,13 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 	bg.Fill2(tinysvg.ColorByName("white"))
 	bg.Stroke2(tinysvg.ColorByName("black"))
 
-	// Position of output node
+	// Position of the output nodes, spread out vertically around the center
 	outputx := width - (marginRight + nodeRadius*2) + imgPadding
-	outputy := (height-(nodeRadius*2))/2 + imgPadding
+	outputSpacing := nodeRadius*2 + betweenPadding*3
+	getOutputPosition := func(outputNumber int) (int, int) {
+		y := (height-(nodeRadius*2))/2 + imgPadding + ((outputNumber*2-(len(outputs)-1))*outputSpacing)/2
+		return outputx, y
+	}
 
 	// For each connected neuron, store it with the distance from the output neuron as the key in a map
 	layerNeurons := make(map[int][]NeuronIndex)
@@ -79,7 +95,7 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 	// Draw node lines first
 	for _, neurons := range layerNeurons {
 		for _, neuronIndex := range neurons {
-			if neuronIndex == net.OutputNode {
+			if net.IsOutput(neuronIndex) {
 				continue
 			}
 			// Find the position of this node circle
@@ -89,9 +105,12 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 				ix, iy := getPosition(inputNeuron)
 				svg.Line(ix+nodeRadius, iy+nodeRadius, x+nodeRadius, y+nodeRadius, lineWidth, "orange")
 			}
-			// Draw the connection to the output node, if it has this node as input
-			if net.AllNodes[net.OutputNode].HasInput(neuronIndex) {
-				svg.Line(x+nodeRadius, y+nodeRadius, outputx+nodeRadius, outputy+nodeRadius, lineWidth, "#0099ff")
+			// Draw the connection to the output nodes, if they have this node as input
+			for i, outputNodeIndex := range outputs {
+				if net.AllNodes[outputNodeIndex].HasInput(neuronIndex) {
+					outputx, outputy := getOutputPosition(i)
+					svg.Line(x+nodeRadius, y+nodeRadius, outputx+nodeRadius, outputy+nodeRadius, lineWidth, "#0099ff")
+				}
 			}
 		}
 	}
@@ -99,7 +118,7 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 	// Then draw the nodes on top, including graph plots
 	for _, neurons := range layerNeurons {
 		for _, neuronIndex := range neurons {
-			if neuronIndex == net.OutputNode {
+			if net.IsOutput(neuronIndex) {
 				continue
 			}
 
@@ -135,7 +154,7 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 				// xv is from -5 to 5
 				xv := (xr - 0.5) * float64(nodeRadius)
 				node := net.AllNodes[neuronIndex]
-				f := ActivationFunctions[node.ActivationFunction]
+				f := node.GetActivationFunction()
 				yv := f(xv)
 				// plot, 3.0 is the amplitude along y
 				yp := float64(ypos) + float64(nodeRadius)*1.35 - (yv * 0.6 * float64(nodeRadius))
@@ -155,7 +174,9 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 							name += " [" + strconv.Itoa(i) + "]"
 						}
 					}
-				} else if neuronIndex == net.OutputNode {
+				} else if net.IsBias(neuronIndex) {
+					name += " [b]"
+				} else if net.IsOutput(neuronIndex) {
 					name += " !"
 				}
 				box := svg.AddRect(int(startx-float64(nodeRadius)*0.4), int(ypos+float64(nodeRadius)*2.5)-5, len(name)*5, 6)
@@ -173,16 +194,22 @@ func (net *Network) OutputSVG(w io.Writer) (int, error) {
 		}
 	}
 
-	// Draw the output node
-	output := svg.AddCircle(outputx+nodeRadius+1, outputy+nodeRadius+1, nodeRadius)
-	output.Fill("magenta")
-	output.Stroke2(tinysvg.ColorByName("black"))
-
-	// Label
-	name := net.AllNodes[net.OutputNode].ActivationFunction.Name() + " [o]"
-	box := svg.AddRect(outputx-nodeRadius/2, (nodeRadius*2)+outputy+1, len(name)*5, 6)
-	box.Fill("black")
-	svg.Text(outputx-nodeRadius/2, (nodeRadius*2)+outputy+6, 8, "Courier", name, "white")
+	// Draw the output nodes
+	for i, outputNodeIndex := range outputs {
+		outputx, outputy := getOutputPosition(i)
+		output := svg.AddCircle(outputx+nodeRadius+1, outputy+nodeRadius+1, nodeRadius)
+		output.Fill("magenta")
+		output.Stroke2(tinysvg.ColorByName("black"))
+
+		// Label
+		name := net.AllNodes[outputNodeIndex].ActivationFunction.Name() + " [o]"
+		if len(outputs) > 1 {
+			name = net.AllNodes[outputNodeIndex].ActivationFunction.Name() + " [o" + strconv.Itoa(i) + "]"
+		}
+		box := svg.AddRect(outputx-nodeRadius/2, (nodeRadius*2)+outputy+1, len(name)*5, 6)
+		box.Fill("black")
+		svg.Text(outputx-nodeRadius/2, (nodeRadius*2)+outputy+6, 8, "Courier", name, "white")
+	}
 
 	// Write the data to the given io.Writer
 	return w.Write(document.Bytes())
@@ -28,3 +28,17 @@ func TestDiagram(t *testing.T) {
 	}
 	os.Remove("test.svg")
 }
+
+func TestDiagramMultipleOutputs(t *testing.T) {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 5,
+		Outputs:                4,
+		InitialConnectionRatio: 0.5,
+		sharedWeight:           0.5,
+	})
+	if err := net.WriteSVG("test_outputs.svg"); err != nil {
+		t.Error(err)
+	}
+	os.Remove("test_outputs.svg")
+}
@@ -1,39 +1,80 @@
 package wann
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // ScorePopulation evaluates a population, given a slice of input numbers.
 // It returns a map with scores, together with the sum of scores.
 func ScorePopulation(population []*Network, weight float64, inputData [][]float64, incorrectOutputMultipliers []float64) (map[int]float64, float64) {
+	return ScorePopulationFitness(population, weight, MultiplierFitness(inputData, incorrectOutputMultipliers))
+}
 
-	scoreMap := make(map[int]float64)
-	scoreSum := 0.0
+// ScorePopulationClasses evaluates a population of networks that have one output node per class,
+// given a slice of input numbers and a slice of class labels with the same length.
+// It returns a map with scores, together with the sum of scores, or an error if the class labels are invalid.
+func ScorePopulationClasses(population []*Network, weight float64, inputData [][]float64, classLabels []int) (map[int]float64, float64, error) {
+	fitness, err := ClassFitness(inputData, classLabels)
+	if err != nil {
+		return nil, 0.0, err
+	}
+	scoreMap, scoreSum := ScorePopulationFitness(population, weight, fitness)
+	return scoreMap, scoreSum, nil
+}
 
-	for i := 0; i < len(population); i++ {
-		net := population[i]
+// ScorePopulationFitness evaluates a population, using the given shared weight and fitness function.
+// It returns a map with scores, together with the sum of scores.
+func ScorePopulationFitness(population []*Network, weight float64, fitness FitnessFunc) (map[int]float64, float64) {
+	return scorePopulation(population, 1, func(net *Network) float64 {
+		net.SetWeight(weight)
+		return fitness(net)
+	})
+}
 
-		if len(net.AllNodes[net.OutputNode].InputNodes) == 0 {
-			// The output node has no input nodes, not great
-			scoreMap[i] = 0.0
-			continue
-		}
+// scorePopulation scores all networks in the population, using the given number of worker goroutines.
+// Each network is only scored by one goroutine, and the scores are summed up in the
+// same order as the population, so the results do not depend on the number of workers.
+// It returns a map with scores, together with the sum of scores.
+func scorePopulation(population []*Network, workers int, score func(net *Network) float64) (map[int]float64, float64) {
+	scores := make([]float64, len(population))
 
-		net.SetWeight(weight)
+	// There is no need for more workers than networks
+	if workers > len(population) {
+		workers = len(population)
+	}
 
-		// Evaluate all the input data examples for this network
-		result := 0.0
-		for i := 0; i < len(inputData); i++ {
-			result += net.Evaluate(inputData[i]) * incorrectOutputMultipliers[i]
+	if workers < 2 {
+		for i, net := range population {
+			scores[i] = score(net)
 		}
+	} else {
+		var wg sync.WaitGroup
+		networkIndices := make(chan int)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range networkIndices {
+					scores[i] = score(population[i])
+				}
+			}()
+		}
+		for i := range population {
+			networkIndices <- i
+		}
+		close(networkIndices)
+		wg.Wait()
+	}
 
-		// The score is how well the network is doing, divided by the network complexity rating
-		score := result / net.Complexity()
-
+	scoreMap := make(map[int]float64, len(population))
+	scoreSum := 0.0
+	for i, score := range scores {
 		scoreSum += score
 		scoreMap[i] = score
 	}
@@ -44,12 +85,16 @@ func ScorePopulation(population []*Network, weight float64, inputData [][]float6
 // * Insert node
 // * Add connection
 // * Change activation function
+// See also ModifyWith, for using other methods and probabilities.
 func (net *Network) Modify(maxIterations int) {
-
 	// Use method 0, 1 or 2
-	method := rand.Intn(3) // up to and not including 3
+	net.mutate(net.random().Intn(3), maxIterations) // up to and not including 3
+}
 
-	// Perform a modfification, using one of the three methods outlined in the paper
+// mutate modifies the network using the given method, which is one of the fields in MutationRates, in order
+func (net *Network) mutate(method int, maxIterations int) {
+
+	// Perform a modfification, using one of the three methods outlined in the paper, or one of the removal methods
 	switch method {
 	case 0:
 		// Insert a node, replacing a randomly chosen existing connection
@@ -76,63 +121,48 @@ func (net *Network) Modify(maxIterations int) {
 	case 2:
 		// Change the activation function to a randomly selected one
 		net.RandomizeActivationFunctionForRandomNeuron()
+	case 3:
+		// Remove a connection, if there are any that can be removed
+		net.RemoveRandomConnection()
+	case 4:
+		// Remove a node, if there are any that are neither input nor output nodes
+		net.RemoveRandomHiddenNode()
+	case 5:
+		// Change where a connection comes from
+		net.RewireRandomConnection()
 	default:
 		panic("implementation error: invalid method number: " + strconv.Itoa(method))
 	}
 }
 
-// Complexity measures the network complexity
-// Will return 1.0 at a minimum
+// Complexity measures the network complexity, using the complexity function that has been set with SetComplexity
+// or in the Config that the network was created with. WeightedComplexity(DefaultComplexityWeights) is used by default.
+// Will return 1.0 at a minimum, for the built-in complexity functions.
 func (net *Network) Complexity() float64 {
-
-	// TODO: These two constants really affect the results. Place them in the Config struct instead.
-
-	// How much should the function complexity matter in relation to the number of connected nodes?
-	const functionComplexityMultiplier = 1.0
-
-	// Weight the number of connected nodes
-	const numberOfNodesMultiplier = 2.0
-
-	// Number of input nodes connected to the output node multiplier
-	const outputNodeInputNodesMultiplier = 3.0
-
-	activationFunctionComplexity := 0.0
-	// Sum the complexity of all activation functions.
-	// This penalizes both slow activation functions and
-	// unconnected nodes.
-	for _, n := range net.AllNodes {
-		if n.Value == nil {
-			activationFunctionComplexity += ComplexityEstimate[n.ActivationFunction]
-		}
+	if net.complexity != nil {
+		return net.complexity(net)
 	}
-	activationFunctionComplexity *= functionComplexityMultiplier
-	// The number of connected nodes should also carry some weight
-	connectedNodes := float64(len(net.Connected())) * numberOfNodesMultiplier
-	// The number of input nodes to the output node
-	outputNodeComplexity := float64(len(net.AllNodes[net.OutputNode].InputNodes)) * outputNodeInputNodesMultiplier
-	// This must always be larger than 0, to avoid divide by zero later
-	return connectedNodes + activationFunctionComplexity + outputNodeComplexity + 1.0
+	return net.weightedComplexity(DefaultComplexityWeights, nil)
 }
 
 // Evolve evolves a neural network, given a slice of training data and a slice of correct output values.
+// If config.Fitness is set, it is used for scoring the networks instead, and the output values may be nil.
+// Networks with one output node per class can be evolved from integer class labels with EvolveClasses.
 // Will overwrite config.Inputs.
 func (config *Config) Evolve(inputData [][]float64, incorrectOutputMultipliers []float64) (*Network, error) {
+	return config.EvolveContext(context.Background(), inputData, incorrectOutputMultipliers)
+}
 
-	// TODO: If the config.initialConnectionRatio field is too low (0.0, for instance), then this function will fail.
-	//       Return with an error if none of the networks in a population has any connections left, then get rid of the "no improvement counter".
-
-	// Initialize, if needed
-	if !config.initialized {
-		config.Init()
-	}
+// EvolveContext is like Evolve, but stops evolving if the given context is cancelled.
+// The context is checked between generations. If it is cancelled, the best network so far
+// is returned together with the error from the context, and the weight is not optimized further.
+func (config *Config) EvolveContext(ctx context.Context, inputData [][]float64, incorrectOutputMultipliers []float64) (*Network, error) {
 
 	inputLength := len(inputData)
 	if inputLength == 0 {
 		return nil, errors.New("no input data")
 	}
 
-	const maxModificationInterationsWhenMutating = 10
-
 	// incorrectOutputMultipliers := make([]float64, len(correctOutputMultipliers))
 	// for i := range correctOutputMultipliers {
 	// 	// Convert from having 0..1 for meaning from incorrect to correct, to -1..1 to mean the same
@@ -141,6 +171,14 @@ func (config *Config) Evolve(inputData [][]float64, incorrectOutputMultipliers [
 	// 	//incorrectOutputMultipliers[i] = -correctOutputMultipliers[i] + 1.0
 	// }
 
+	// Use config.Outputs output nodes, also if EvolveClasses has been used with this configuration before
+	config.classes = 0
+
+	// Use the custom fitness function, if one is configured
+	if config.Fitness != nil {
+		return config.evolve(ctx, inputData, config.Fitness)
+	}
+
 	if len(incorrectOutputMultipliers) == 1 && inputLength != 1 {
 		// Assume the first slice of floats in the input data is the correct and that the rest are examples of being wrong
 		for i := 1; i < inputLength; i++ {
@@ -151,23 +189,85 @@ func (config *Config) Evolve(inputData [][]float64, incorrectOutputMultipliers [
 		return nil, errors.New("the length of the input data and the slice of output multipliers differs")
 	}
 
-	config.inputs = len(inputData[0])
+	return config.evolve(ctx, inputData, MultiplierFitness(inputData, incorrectOutputMultipliers))
+}
 
-	population := make([]*Network, config.PopulationSize)
+// EvolveClasses evolves a neural network with one output node per class, given a slice of training data
+// and a slice of class labels (0, 1, 2 and so on) with the same length.
+// The networks get one output node per class, or config.Outputs output nodes if that is more,
+// but config.Outputs is not changed. Will overwrite config.Inputs.
+// The class of new input data can then be found with the Classify method of the returned network.
+func (config *Config) EvolveClasses(inputData [][]float64, classLabels []int) (*Network, error) {
+	return config.EvolveClassesContext(context.Background(), inputData, classLabels)
+}
+
+// EvolveClassesContext is like EvolveClasses, but stops evolving if the given context is cancelled.
+// The best network so far is then returned, together with the error from the context.
+func (config *Config) EvolveClassesContext(ctx context.Context, inputData [][]float64, classLabels []int) (*Network, error) {
+	if len(inputData) == 0 {
+		return nil, errors.New("no input data")
+	}
+	fitness, err := ClassFitness(inputData, classLabels)
+	if err != nil {
+		return nil, err
+	}
+	maxLabel := 0
+	for _, label := range classLabels {
+		if label > maxLabel {
+			maxLabel = label
+		}
+	}
+	config.classes = maxLabel + 1
+	// Use the custom fitness function, if one is configured
+	if config.Fitness != nil {
+		return config.evolve(ctx, inputData, config.Fitness)
+	}
+	return config.evolve(ctx, inputData, fitness)
+}
+
+// evolve evolves a neural network, given a slice of training data and a fitness function for scoring each network
+func (config *Config) evolve(ctx context.Context, inputData [][]float64, fitness FitnessFunc) (*Network, error) {
+
+	// TODO: If the config.initialConnectionRatio field is too low (0.0, for instance), then this function will fail.
+	//       Return with an error if none of the networks in a population has any connections left, then get rid of the "no improvement counter".
+
+	if config.MultiObjective && config.CompatibilityThreshold > 0 {
+		return nil, errors.New("multi-objective optimisation can not be combined with speciation")
+	}
+
+	if err := config.checkAllowedActivationFunctions(); err != nil {
+		return nil, err
+	}
+
+	// Initialize, if needed
+	if !config.initialized {
+		config.Init()
+	}
+	config.paretoFront = nil
+	config.weightScores = nil
 
-	// Initialize the population
-	for i := 0; i < config.PopulationSize; i++ {
-		n := NewNetwork(config)
-		population[i] = &n
-		population[i].UpdateNetworkPointers()
+	const maxModificationInterationsWhenMutating = 10
+
+	logger := config.logger()
+
+	config.inputs = len(inputData[0])
+
+	// Score the population using the given shared weight, and one goroutine per worker
+	scoreWithWeight := func(population []*Network, weight float64) (map[int]float64, float64) {
+		return scorePopulation(population, config.workers(), func(net *Network) float64 {
+			net.SetWeight(weight)
+			return fitness(net)
+		})
 	}
 
+	population := make([]*Network, config.PopulationSize)
+
 	var (
 		bestNetwork *Network
 
 		// Keep track of the best scores
-		bestScore     float64
-		lastBestScore float64
+		bestScore  float64
+		bestScores []float64 // The best score for each generation
 
 		noImprovementCounter int // Counts how many times the best score has been stagnant
 
@@ -176,137 +276,311 @@ func (config *Config) Evolve(inputData [][]float64, incorrectOutputMultipliers [
 
 		// Keep track of the worst scores
 		worstScore float64
+
+		// The species, if speciation is enabled
+		speciesList []*species
+
+		// The first generation to evolve
+		startGeneration int
 	)
 
-	if config.Verbose {
-		fmt.Printf("Starting evolution with population size %d, for %d generations.\n", config.PopulationSize, config.Generations)
+	if cp := config.resume; cp != nil {
+		// Continue from the checkpoint that was loaded by ResumeFrom
+		config.resume = nil
+		if len(cp.Population) != config.PopulationSize {
+			return nil, fmt.Errorf("the population size of the checkpoint is %d, but the configured population size is %d", len(cp.Population), config.PopulationSize)
+		}
+		for _, net := range cp.Population {
+			if len(net.InputNodes) != config.inputs {
+				return nil, fmt.Errorf("the networks in the checkpoint have %d input nodes, but the input data has %d numbers per row", len(net.InputNodes), config.inputs)
+			}
+			net.SetRand(config.Rand)
+			net.innovations = config.innovationHistory()
+			net.complexity = config.complexity()
+			net.allowed = config.AllowedActivationFunctions
+		}
+		population = cp.Population
+		bestNetwork = cp.BestNetwork
+		bestScore = float64(cp.BestScore)
+		worstScore = float64(cp.WorstScore)
+		for _, score := range cp.BestScores {
+			bestScores = append(bestScores, float64(score))
+		}
+		noImprovementCounter = cp.NoImprovementCounter
+		for _, representative := range cp.Species {
+			speciesList = append(speciesList, &species{representative: representative})
+		}
+		startGeneration = cp.Generation
+	} else {
+		// Initialize the population, with a fresh innovation history
+		config.innovations = newInnovationHistory(0)
+		for i := 0; i < config.PopulationSize; i++ {
+			n := NewNetwork(config)
+			population[i] = &n
+			population[i].UpdateNetworkPointers()
+		}
+	}
+
+	// Write a checkpoint where the given generation is the next one to evolve, if configured
+	lastCheckpointGeneration := startGeneration
+	saveCheckpoint := func(generation int) error {
+		if config.CheckpointFile == "" || generation == lastCheckpointGeneration {
+			return nil
+		}
+		cp := &checkpoint{
+			Generation:           generation,
+			Seed:                 config.seed,
+			Population:           population,
+			BestNetwork:          bestNetwork,
+			BestScore:            jsonFloat(bestScore),
+			WorstScore:           jsonFloat(worstScore),
+			NoImprovementCounter: noImprovementCounter,
+		}
+		for _, s := range speciesList {
+			cp.Species = append(cp.Species, s.representative)
+		}
+		for _, score := range bestScores {
+			cp.BestScores = append(cp.BestScores, jsonFloat(score))
+		}
+		if err := config.writeCheckpoint(cp); err != nil {
+			return err
+		}
+		lastCheckpointGeneration = generation
+		logger.Debug("wrote checkpoint", "generation", generation-1, "file", config.CheckpointFile)
+		return nil
 	}
 
+	logger.Info("starting evolution", "populationSize", config.PopulationSize, "generations", config.Generations, "selector", config.selector().Name())
+
+	startTime := time.Now()
+
 	// For each generation, evaluate and modify the networks
-	for j := 0; j < config.Generations; j++ {
+	for j := startGeneration; j < config.Generations; j++ {
 
-		bestNetwork = nil
+		// Stop if the context has been cancelled, and return the best network so far
+		if err := ctx.Err(); err != nil {
+			logger.Info("stopping evolution", "generation", j, "reason", err)
+			if cpErr := saveCheckpoint(j); cpErr != nil {
+				return bestNetwork, cpErr
+			}
+			return bestNetwork, err
+		}
 
-		// Initialize the scores with unlikely values
-		// TODO: Use the first network in the population for initializing these instead
-		first := true
+		// Seed the pseudo-random number generator for each generation, so that an evolution that
+		// is resumed from a checkpoint gives the same results as one that was not interrupted.
+		// A generator that was given in config.Rand is used as it is.
+		if config.ownRand {
+			config.Rand.Seed(config.generationSeed(j))
+		}
 
-		// Random weight from -2.0 to 2.0
-		w := rand.Float64()
+		// Use the configured series of shared weights, or a random weight from 0.0 to 1.0
+		weights := config.WeightSamples
+		if len(weights) == 0 {
+			weights = []float64{config.Rand.Float64()}
+		}
 
-		// The scores for this generation (using a random shared weight within ScorePopulation).
+		// The scores for this generation (using the shared weights within ScorePopulation).
 		// CorrectOutputMultipliers gives weight to the "correct" or "wrong" results, with the same index as the inputData
-		// Score each network in the population.
-		scoreMap, scoreSum := ScorePopulation(population, w, inputData, incorrectOutputMultipliers)
+		// Score each network in the population, once per weight, and aggregate the scores.
+		weightScores := scoreWeightSeries(population, weights, scoreWithWeight)
+		scoreMap, scoreSum, bestWeights := config.aggregateScores(weightScores, weights)
 
 		// Sort by score
 		scoreList := SortByValue(scoreMap)
 
-		// Handle the best score stats
-		if first {
-			lastBestScore = 0.0
-			bestScore = scoreList[0].Value
-			worstScore = scoreList[len(scoreList)-1].Value
-			bestNetwork = population[scoreList[0].Key]
-			bestNetwork.SetWeight(w)
-			first = false
-		} else {
-			lastBestScore = bestScore
-			if scoreList[0].Value > bestScore {
-				bestScore = scoreList[0].Value
-			}
-		}
-		if bestScore > lastBestScore {
-			bestNetwork = population[scoreList[0].Key]
-			bestNetwork.SetWeight(w)
+		generationBestScore := scoreList[0].Value
+		generationWorstScore := scoreList[len(scoreList)-1].Value
+
+		// Handle the best score stats. The best network is copied, since the
+		// networks in the population may be modified in later generations.
+		if bestNetwork == nil || generationBestScore > bestScore {
+			bestScore = generationBestScore
+			bestNetwork = population[scoreList[0].Key].Copy()
+			bestNetwork.SetWeight(bestWeights[scoreList[0].Key])
 			noImprovementCounter = 0
 		} else {
 			noImprovementCounter++
 		}
 
+		// Handle the worst score stats
+		if len(bestScores) == 0 || generationWorstScore < worstScore {
+			worstScore = generationWorstScore
+		}
+
+		bestScores = append(bestScores, generationBestScore)
+
 		// Handle the average score stats
 		averageScore = scoreSum / float64(config.PopulationSize)
 
-		// Handle the worst score stats
-		if scoreList[len(scoreList)-1].Value < worstScore {
-			worstScore = scoreList[len(scoreList)-1].Value
+		// Divide the population into species, if enabled
+		if config.CompatibilityThreshold > 0 {
+			speciesList = config.speciate(population, speciesList)
+			logger.Debug("speciated the population", "generation", j, "species", len(speciesList))
 		}
 
-		if bestNetwork == nil {
-			panic("implementation error: no best network")
+		// Rank the population by Pareto front and crowding distance, if multi-objective optimisation is enabled
+		var (
+			ranking         []int
+			paretoFrontSize int
+		)
+		if config.MultiObjective {
+			objectives := make([][]float64, len(population))
+			for i, net := range population {
+				objectives[i] = config.objectives(net, weightScores[i])
+			}
+			var fronts [][]int
+			ranking, fronts = paretoRanking(objectives)
+			paretoFrontSize = len(fronts[0])
+			// Keep copies of the networks in the first front, since the networks in the population may be modified later
+			config.paretoFront = make([]*Network, 0, paretoFrontSize)
+			for _, networkIndex := range fronts[0] {
+				net := population[networkIndex].Copy()
+				net.SetWeight(bestWeights[networkIndex])
+				config.paretoFront = append(config.paretoFront, net)
+			}
+			logger.Debug("ranked the population by Pareto front", "generation", j, "fronts", len(fronts), "firstFront", paretoFrontSize)
 		}
 
-		if config.Verbose {
-			fmt.Printf("[generation %d] worst score = %f, average score = %f, best score = %f\n", j, worstScore, averageScore, bestScore)
-			//fmt.Printf("[generation %d] worst score = %f, average score = %f, best score = %f, no improvement counter for this generation = %d\n", j, worstScore, averageScore, bestScore, noImprovementCounter)
-			if noImprovementCounter > 0 {
-				fmt.Printf("No improvement in the best score for the last %d generations\n", noImprovementCounter)
-			}
+		// Report the statistics for this generation, if a callback is configured
+		if config.OnGeneration != nil {
+			config.OnGeneration(GenerationStats{
+				Generation:           j,
+				BestScore:            generationBestScore,
+				AverageScore:         averageScore,
+				WorstScore:           generationWorstScore,
+				Weights:              weights,
+				WeightScores:         weightScores[scoreList[0].Key],
+				AllTimeBestScore:     bestScore,
+				BestNetwork:          bestNetwork,
+				ConnectedNodes:       len(bestNetwork.Connected()),
+				Complexities:         complexities(population),
+				Species:              len(speciesList),
+				ParetoFront:          paretoFrontSize,
+				NoImprovementCounter: noImprovementCounter,
+				Elapsed:              time.Since(startTime),
+			})
 		}
 
-		// Only keep the best 7%
-		bestFractionCountdown := int(float64(len(population)) * 0.07)
+		logger.Info("generation", "generation", j, "worstScore", worstScore, "averageScore", averageScore, "bestScore", bestScore)
+		if noImprovementCounter > 0 {
+			logger.Debug("no improvement in the best score", "generations", noImprovementCounter)
+		}
 
-		goodNetworks := make([]*Network, 0, bestFractionCountdown)
+		if config.CompatibilityThreshold > 0 {
+			// Let each species have offspring, in proportion to the shared scores of its members
+			population = config.reproduceSpecies(population, scoreMap, speciesList, maxModificationInterationsWhenMutating)
+		} else {
+			// Keep the best networks, and replace the rest with the offspring of networks chosen by the selection strategy.
+			// The networks are ranked by score (descending order), or by Pareto front if multi-objective optimisation is enabled.
+			selector := config.selector()
+			elites := selector.Elites(len(population))
+			ranked := make([]*Network, len(scoreList))
+			rankedScores := make([]float64, len(scoreList))
+			if ranking != nil {
+				for rank, networkIndex := range ranking {
+					ranked[rank] = population[networkIndex]
+					// The selector only needs scores that are sorted in descending order
+					rankedScores[rank] = -float64(rank)
+				}
+				// Always keep the networks in the first Pareto front, but at most half the population
+				if front := min(paretoFrontSize, len(population)/2); front > elites {
+					elites = front
+				}
+			} else {
+				// p.Key is the network index and p.Value is the network score
+				ranking = make([]int, len(scoreList))
+				for rank, p := range scoreList {
+					ranking[rank] = p.Key
+					ranked[rank] = population[p.Key]
+					rankedScores[rank] = p.Value
+				}
+			}
+			pick := func() int {
+				return selector.Select(rankedScores, config.Rand)
+			}
+			for rank, networkIndex := range ranking {
+				if rank < elites {
+					continue
+				}
+				// Replace the "bad" network with a modified copy of a "good" one, or a crossover of two
+				// It's important that this is a pointer to a Network and not
+				// a bare Network, so that the node .Net pointers are correct.
+				population[networkIndex] = config.offspring(ranked, pick, maxModificationInterationsWhenMutating)
+			}
+		}
 
-		// Now loop over all networks, sorted by score (descending order)
-		// p.Key is the network index
-		// p.Value is the network score
-		for _, p := range scoreList {
-			networkIndex := p.Key
-			if bestFractionCountdown > 0 {
-				bestFractionCountdown--
-				// In the best third of the networks
-				goodNetworks = append(goodNetworks, population[networkIndex])
-				continue
+		// Check if any of the stopping criteria are met
+		stop := ""
+		switch {
+		case config.MaxIterationsWithoutBestImprovement > 0 && noImprovementCounter >= config.MaxIterationsWithoutBestImprovement:
+			stop = fmt.Sprintf("no improvement in the best score for %d generations", noImprovementCounter)
+		case config.UseTargetScore && bestScore >= config.TargetScore:
+			stop = fmt.Sprintf("reached the target score %f", config.TargetScore)
+		case config.TimeBudget > 0 && time.Since(startTime) >= config.TimeBudget:
+			stop = fmt.Sprintf("used up the time budget of %v", config.TimeBudget)
+		}
+
+		// Write a checkpoint, if configured
+		if stop != "" || config.CheckpointInterval < 1 || (j+1)%config.CheckpointInterval == 0 || j+1 == config.Generations {
+			if err := saveCheckpoint(j + 1); err != nil {
+				return bestNetwork, err
 			}
-			// // If there has not been any improvement to the best score lately, randomize the bad half
-			// if noImprovementCounter > 100 {
-			// 	n := NewNetwork(config)
-			// 	population[networkIndex] = &n
-			// 	continue
-			// }
-			randomGoodNetwork := goodNetworks[rand.Intn(len(goodNetworks))]
-			randomGoodNetworkCopy := randomGoodNetwork.Copy()
-			randomGoodNetworkCopy.Modify(maxModificationInterationsWhenMutating)
-			// Replace the "bad" network with the modified copy of a "good" one
-			// It's important that this is a pointer to a Network and not
-			// a bare Network, so that the node .Net pointers are correct.
-			population[networkIndex] = randomGoodNetworkCopy
-		}
-		// if noImprovementCounter > 100 {
-		// 	noImprovementCounter = 0
-		// }
+		}
+
+		if stop != "" {
+			logger.Info("stopping evolution", "generation", j, "reason", stop)
+			break
+		}
 	}
-	if config.Verbose {
-		fmt.Printf("[all time best network, random weight  ] weight=%f score=%f\n", bestNetwork.Weight, bestScore)
+	// Check if the best network is nil, just in case
+	if bestNetwork == nil {
+		return nil, errors.New("the total best network is nil")
+	}
+
+	if len(config.WeightSamples) == 0 {
+		logger.Info("all time best network, random weight", "weight", bestNetwork.Weight, "score", bestScore)
+	} else {
+		logger.Info("all time best network, weight samples", "weight", bestNetwork.Weight, "aggregation", config.WeightAggregation.Name(), "score", bestScore)
 	}
 
 	// Now find the best weight for the best network, using a population of 1
 	// and a step size of 0.0001 for the weight
 	population = []*Network{bestNetwork}
-	bestWeight := -2.0
+	evolvedWeight := bestNetwork.Weight
+	bestWeight := evolvedWeight
+	bestWeightScore := math.Inf(-1)
 	for w := -2.0; w <= 2.0; w += 0.0001 {
-		scoreMap, _ := ScorePopulation(population, w, inputData, incorrectOutputMultipliers)
+		if err := ctx.Err(); err != nil {
+			// Keep the weight that the network was evolved with
+			bestNetwork.SetWeight(evolvedWeight)
+			return bestNetwork, err
+		}
+		if config.TimeBudget > 0 && time.Since(startTime) >= config.TimeBudget {
+			// Keep the weight that the network was evolved with, since only some of the weights have been tried
+			logger.Info("stopping the search for the best weight", "weight", w, "reason", fmt.Sprintf("used up the time budget of %v", config.TimeBudget))
+			bestNetwork.SetWeight(evolvedWeight)
+			return bestNetwork, nil
+		}
+		scoreMap, _ := scoreWithWeight(population, w)
 		// Handle the best score stats
-		if scoreMap[0] > bestScore {
-			bestScore = scoreMap[0]
-			population[0].SetWeight(w)
+		if scoreMap[0] > bestWeightScore {
+			bestWeightScore = scoreMap[0]
 			bestWeight = w
 		}
 	}
 
-	// Check if the best network is nil, just in case
-	if bestNetwork == nil {
-		return nil, errors.New("the total best network is nil")
+	// Find and report the score for each of the weight samples
+	config.weightScores = make([]float64, len(config.WeightSamples))
+	for i, w := range config.WeightSamples {
+		scoreMap, _ := scoreWithWeight(population, w)
+		config.weightScores[i] = scoreMap[0]
+		logger.Debug("all time best network, weight sample", "weight", w, "score", scoreMap[0])
 	}
 
 	// Save the best weight for the network
 	bestNetwork.SetWeight(bestWeight)
 
-	if config.Verbose {
-		fmt.Printf("[all time best network, optimal weight ] weight=%f best score=%f\n", bestNetwork.Weight, bestScore)
-	}
+	logger.Info("all time best network, optimal weight", "weight", bestNetwork.Weight, "score", bestWeightScore)
 
 	return bestNetwork, nil
 }
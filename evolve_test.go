@@ -0,0 +1,278 @@
+package wann
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScorePopulationWorkers(t *testing.T) {
+	rand.Seed(commonSeed)
+	config := &Config{
+		inputs:                 6,
+		InitialConnectionRatio: 0.5,
+	}
+	population := make([]*Network, 50)
+	for i := range population {
+		net := NewNetwork(config)
+		net.UpdateNetworkPointers()
+		net.Modify(10)
+		population[i] = &net
+	}
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 1.0, 0.0, 0.0},
+	}
+	fitness := MultiplierFitness(inputData, []float64{1.0, -1.0, -1.0, -1.0})
+	score := func(net *Network) float64 {
+		net.SetWeight(0.5)
+		return fitness(net)
+	}
+	serialScores, serialSum := scorePopulation(population, 1, score)
+	concurrentScores, concurrentSum := scorePopulation(population, 8, score)
+	if serialSum != concurrentSum {
+		t.Errorf("the sum of scores differs: %f != %f", serialSum, concurrentSum)
+	}
+	for i := range population {
+		if serialScores[i] != concurrentScores[i] {
+			t.Errorf("the score for network %d differs: %f != %f", i, serialScores[i], concurrentScores[i])
+		}
+	}
+}
+
+func TestScoreWeights(t *testing.T) {
+	rand.Seed(commonSeed)
+	config := &Config{
+		inputs:                 3,
+		InitialConnectionRatio: 1.0,
+		WeightSamples:          DefaultWeightSamples,
+		WeightAggregation:      AggregateMin,
+	}
+	net := NewNetwork(config)
+	population := []*Network{&net}
+	// A score function that only depends on the shared weight
+	scoreFunc := func(population []*Network, weight float64) (map[int]float64, float64) {
+		return scorePopulation(population, 1, func(net *Network) float64 {
+			return -weight * weight
+		})
+	}
+	scoreMap, scoreSum, bestWeights := config.scoreWeights(population, config.WeightSamples, scoreFunc)
+	if scoreMap[0] != -4.0 || scoreSum != -4.0 {
+		t.Errorf("expected the lowest score, -4, got %f", scoreMap[0])
+	}
+	if bestWeights[0] != -0.5 {
+		t.Errorf("expected -0.5 to be the best weight, got %f", bestWeights[0])
+	}
+}
+
+func TestConcurrentEvolve(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 1.0, 0.0, 0.0},
+	}
+	multipliers := []float64{1.0, -1.0, -1.0, -1.0}
+	// Evolve two networks concurrently, using the same random seed
+	results := make([]string, 2)
+	done := make(chan bool)
+	for i := range results {
+		go func(i int) {
+			config := &Config{
+				InitialConnectionRatio: 0.3,
+				Generations:            10,
+				PopulationSize:         40,
+				RandomSeed:             commonSeed,
+				Workers:                2,
+			}
+			net, err := config.Evolve(inputData, multipliers)
+			if err != nil {
+				t.Error(err)
+			} else {
+				results[i] = net.String()
+			}
+			done <- true
+		}(i)
+		// Also use the global pseudo-random number generator in the mean time
+		rand.Float64()
+	}
+	<-done
+	<-done
+	if results[0] != results[1] {
+		t.Errorf("two evolutions with the same random seed gave different networks:\n%s\n%s", results[0], results[1])
+	}
+}
+
+// seedCounter is a rand.Source that counts the number of times it is seeded
+type seedCounter struct {
+	rand.Source
+	seeds int
+}
+
+// Seed seeds the wrapped source, and counts the call
+func (s *seedCounter) Seed(seed int64) {
+	s.seeds++
+	s.Source.Seed(seed)
+}
+
+func TestGivenRand(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0},
+	}
+	source := &seedCounter{Source: rand.NewSource(commonSeed)}
+	r := rand.New(source)
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            3,
+		PopulationSize:         10,
+		RandomSeed:             commonSeed,
+		Rand:                   r,
+	}
+	config.Init()
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if config.Rand != r {
+		t.Error("expected the given pseudo-random number generator to be used")
+	}
+	if source.seeds != 0 {
+		t.Errorf("expected the given pseudo-random number generator to be used as it is, but it was seeded %d times", source.seeds)
+	}
+}
+
+func TestEvolveContextCancel(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            1000,
+		PopulationSize:         20,
+		RandomSeed:             commonSeed,
+		Workers:                1,
+	}
+	// Cancel the evolution while the third generation is being scored
+	var mut sync.Mutex
+	calls := 0
+	fitness := MultiplierFitness(inputData, []float64{1.0, -1.0})
+	config.Fitness = func(net *Network) float64 {
+		mut.Lock()
+		calls++
+		if calls == 2*config.PopulationSize+1 {
+			cancel()
+		}
+		mut.Unlock()
+		return fitness(net)
+	}
+	net, err := config.EvolveContext(ctx, inputData, nil)
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+	if net == nil {
+		t.Fatal("expected the best network so far, got nil")
+	}
+	if calls != 3*config.PopulationSize {
+		t.Errorf("expected the evolution to stop after 3 generations, but the fitness function was called %d times", calls)
+	}
+
+	// Cancelling before the first generation gives no network
+	net, err = config.EvolveContext(ctx, inputData, nil)
+	if err != context.Canceled || net != nil {
+		t.Errorf("expected no network and context.Canceled, got: %v, %v", net, err)
+	}
+}
+
+func TestStoppingCriteria(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+	}
+	// The number of times the network is scored when finding the best weight after evolving
+	weightSteps := 0
+	for w := -2.0; w <= 2.0; w += 0.0001 {
+		weightSteps++
+	}
+	const populationSize = 10
+	for _, tc := range []struct {
+		name        string
+		config      Config
+		generations int
+		weightSteps int
+	}{
+		{"max iterations without improvement", Config{MaxIterationsWithoutBestImprovement: 3}, 4, weightSteps},
+		{"target score", Config{TargetScore: 1.0, UseTargetScore: true}, 1, weightSteps},
+		{"target score of 0", Config{UseTargetScore: true}, 1, weightSteps},
+		{"target score, not used", Config{TargetScore: 1.0}, 7, weightSteps},
+		// The time budget is also used up before finding the best weight
+		{"time budget", Config{TimeBudget: time.Nanosecond}, 1, 0},
+		{"generations", Config{}, 7, weightSteps},
+	} {
+		config := tc.config
+		config.InitialConnectionRatio = 0.5
+		config.Generations = 7
+		config.PopulationSize = populationSize
+		config.RandomSeed = commonSeed
+		calls := 0
+		// The score never improves after the first generation
+		config.Fitness = func(net *Network) float64 {
+			calls++
+			return 1.0
+		}
+		config.Workers = 1
+		if _, err := config.Evolve(inputData, nil); err != nil {
+			t.Fatal(err)
+		}
+		if expected := tc.generations*populationSize + tc.weightSteps; calls != expected {
+			t.Errorf("%s: expected %d calls to the fitness function, got %d", tc.name, expected, calls)
+		}
+	}
+}
+
+func TestOnGeneration(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	var allStats []GenerationStats
+	config := &Config{
+		InitialConnectionRatio: 0.3,
+		Generations:            8,
+		PopulationSize:         30,
+		RandomSeed:             commonSeed,
+		OnGeneration: func(stats GenerationStats) {
+			allStats = append(allStats, stats)
+		},
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if len(allStats) != config.Generations {
+		t.Fatalf("expected %d calls to OnGeneration, got %d", config.Generations, len(allStats))
+	}
+	for i, stats := range allStats {
+		if stats.Generation != i {
+			t.Errorf("expected generation %d, got %d", i, stats.Generation)
+		}
+		if stats.WorstScore > stats.AverageScore || stats.AverageScore > stats.BestScore || stats.BestScore > stats.AllTimeBestScore {
+			t.Errorf("generation %d: the scores are out of order: %v", i, stats)
+		}
+		if i > 0 && stats.AllTimeBestScore < allStats[i-1].AllTimeBestScore {
+			t.Errorf("generation %d: the all time best score decreased", i)
+		}
+		if stats.BestNetwork == nil || stats.ConnectedNodes != len(stats.BestNetwork.Connected()) {
+			t.Errorf("generation %d: wrong best network or connected node count", i)
+		}
+		if len(stats.Complexities) != config.PopulationSize || !sort.Float64sAreSorted(stats.Complexities) {
+			t.Errorf("generation %d: expected a sorted complexity for each network: %v", i, stats.Complexities)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package wann
+
+import (
+	"fmt"
+)
+
+// FitnessFunc scores a network, where a higher score is better.
+// The shared weight of the network has already been set when the function is called.
+// When evolving, the function is called concurrently for different networks,
+// so it must not modify anything but the given network.
+type FitnessFunc func(net *Network) float64
+
+// hasOutputConnections checks if at least one of the output nodes has input nodes
+func (net *Network) hasOutputConnections() bool {
+	for _, outputNodeIndex := range net.Outputs() {
+		if len(net.AllNodes[outputNodeIndex].InputNodes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiplierFitness returns a fitness function that evaluates the network for each input data example and
+// multiplies the result with the corresponding output multiplier, typically 1 for "correct" and -1 for "wrong".
+// The sum is divided by the complexity of the network.
+func MultiplierFitness(inputData [][]float64, incorrectOutputMultipliers []float64) FitnessFunc {
+	return func(net *Network) float64 {
+		if len(net.AllNodes[net.OutputNode].InputNodes) == 0 {
+			// The output node has no input nodes, not great
+			return 0.0
+		}
+
+		// Evaluate all the input data examples for this network
+		result := 0.0
+		for i, output := range net.EvaluateBatch(inputData) {
+			result += output * incorrectOutputMultipliers[i]
+		}
+
+		// The score is how well the network is doing, divided by the network complexity rating
+		return result / net.Complexity()
+	}
+}
+
+// checkClassLabels checks that there is one class label per input data example, and that none of them are negative
+func checkClassLabels(inputData [][]float64, classLabels []int) error {
+	if len(classLabels) != len(inputData) {
+		return fmt.Errorf("expected %d class labels, one per input data example, got %d", len(inputData), len(classLabels))
+	}
+	for _, label := range classLabels {
+		if label < 0 {
+			return fmt.Errorf("class labels can not be negative, got %d", label)
+		}
+	}
+	return nil
+}
+
+// ClassFitness returns a fitness function for networks that have one output node per class.
+// The output node for the correct class is rewarded and the other output nodes are penalized,
+// for each input data example. The sum is divided by the complexity of the network.
+// Returns an error if there is not one class label per input data example, or if a label is negative.
+func ClassFitness(inputData [][]float64, classLabels []int) (FitnessFunc, error) {
+	if err := checkClassLabels(inputData, classLabels); err != nil {
+		return nil, err
+	}
+	return func(net *Network) float64 {
+		if !net.hasOutputConnections() {
+			// None of the output nodes have input nodes, not great
+			return 0.0
+		}
+
+		// Reward the output node for the correct class and penalize the other output nodes
+		multipliers := classMultipliers(classLabels, len(net.Outputs()))
+
+		// Evaluate all the input data examples for this network
+		result := 0.0
+		for i, outputs := range net.EvaluateAllBatch(inputData) {
+			for j, output := range outputs {
+				result += output * multipliers[i][j]
+			}
+		}
+
+		// The score is how well the network is doing, divided by the network complexity rating
+		return result / net.Complexity()
+	}, nil
+}
+
+// AccuracyFitness returns a fitness function for networks that have one output node per class.
+// The score is the fraction of input data examples that are classified correctly,
+// divided by the complexity of the network.
+// Returns an error if there is not one class label per input data example, or if a label is negative.
+func AccuracyFitness(inputData [][]float64, classLabels []int) (FitnessFunc, error) {
+	if err := checkClassLabels(inputData, classLabels); err != nil {
+		return nil, err
+	}
+	return func(net *Network) float64 {
+		if len(inputData) == 0 || !net.hasOutputConnections() {
+			return 0.0
+		}
+		correct := 0
+		for i, label := range net.ClassifyBatch(inputData) {
+			if label == classLabels[i] {
+				correct++
+			}
+		}
+		accuracy := float64(correct) / float64(len(inputData))
+		return accuracy / net.Complexity()
+	}, nil
+}
+
+// MeanSquaredErrorFitness returns a fitness function for regression, where targets contains the
+// expected values of the output nodes, for each input data example.
+// The score is 1 / (1 + mean squared error), divided by the complexity of the network.
+// Returns an error if there is not one slice of target values per input data example.
+func MeanSquaredErrorFitness(inputData [][]float64, targets [][]float64) (FitnessFunc, error) {
+	if len(targets) != len(inputData) {
+		return nil, fmt.Errorf("expected %d slices of target values, one per input data example, got %d", len(inputData), len(targets))
+	}
+	return func(net *Network) float64 {
+		if !net.hasOutputConnections() {
+			return 0.0
+		}
+		squaredErrorSum := 0.0
+		counter := 0
+		for i, outputs := range net.EvaluateAllBatch(inputData) {
+			for j, output := range outputs {
+				if j >= len(targets[i]) {
+					break
+				}
+				diff := output - targets[i][j]
+				squaredErrorSum += diff * diff
+				counter++
+			}
+		}
+		if counter == 0 {
+			return 0.0
+		}
+		meanSquaredError := squaredErrorSum / float64(counter)
+		return (1.0 / (1.0 + meanSquaredError)) / net.Complexity()
+	}, nil
+}
@@ -0,0 +1,98 @@
+package wann
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// newIdentityNetwork returns a network where input node i is connected to output node i,
+// and where all activation functions are linear
+func newIdentityNetwork(n int) *Network {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 n,
+		Outputs:                n,
+		InitialConnectionRatio: 0.0,
+		sharedWeight:           1.0,
+	})
+	for i, outputNodeIndex := range net.OutputNodes {
+		net.AllNodes[outputNodeIndex].ActivationFunction = Linear
+		net.AllNodes[net.InputNodes[i]].ActivationFunction = Linear
+		if err := net.AddConnection(net.InputNodes[i], outputNodeIndex); err != nil {
+			panic(err)
+		}
+	}
+	return &net
+}
+
+func TestAccuracyFitness(t *testing.T) {
+	net := newIdentityNetwork(2)
+	inputData := [][]float64{{1.0, 0.0}, {0.0, 1.0}, {0.2, 0.1}, {0.3, 0.4}}
+	perfectFitness, err := AccuracyFitness(inputData, []int{0, 1, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	halfFitness, err := AccuracyFitness(inputData, []int{0, 1, 1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	perfect, half := perfectFitness(net), halfFitness(net)
+	if perfect != 2*half || perfect != 1.0/net.Complexity() {
+		t.Errorf("expected a perfect accuracy score to be twice as high as a half accuracy score, got %f and %f", perfect, half)
+	}
+	// There must be one class label per input data example, and no negative labels
+	for _, classLabels := range [][]int{{0, 1}, {0, 1, 0, -1}} {
+		if _, err := AccuracyFitness(inputData, classLabels); err == nil {
+			t.Errorf("expected an error for the class labels %v", classLabels)
+		}
+		if _, err := ClassFitness(inputData, classLabels); err == nil {
+			t.Errorf("expected an error for the class labels %v", classLabels)
+		}
+	}
+}
+
+func TestMeanSquaredErrorFitness(t *testing.T) {
+	net := newIdentityNetwork(2)
+	inputData := [][]float64{{1.0, 0.0}, {0.0, 1.0}}
+	fitness, err := MeanSquaredErrorFitness(inputData, inputData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact := fitness(net)
+	if exact != 1.0/net.Complexity() {
+		t.Errorf("expected the highest possible score when there is no error, got %f", exact)
+	}
+	fitness, err = MeanSquaredErrorFitness(inputData, [][]float64{{0.0, 1.0}, {1.0, 0.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if off := fitness(net); off >= exact {
+		t.Errorf("expected a lower score when the outputs are wrong, got %f", off)
+	}
+	if _, err := MeanSquaredErrorFitness(inputData, [][]float64{{1.0, 0.0}}); err == nil {
+		t.Error("expected an error when there are fewer targets than input data examples")
+	}
+}
+
+func TestCustomFitness(t *testing.T) {
+	inputData := [][]float64{{0.0, 1.0, 0.0}, {1.0, 0.0, 1.0}}
+	called := false
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            2,
+		PopulationSize:         10,
+		RandomSeed:             commonSeed,
+		Workers:                1,
+		Fitness: func(net *Network) float64 {
+			called = true
+			// Prefer networks with few connected nodes
+			return 1.0 / float64(len(net.Connected()))
+		},
+	}
+	if _, err := config.Evolve(inputData, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Error("the custom fitness function was not used")
+	}
+}
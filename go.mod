@@ -1,9 +1,11 @@
 module github.com/xyproto/wann
 
-go 1.11
+go 1.21
 
 require (
 	github.com/dave/jennifer v1.5.0
 	github.com/xyproto/af v0.0.0-20191018214415-1a8887381bd3
 	github.com/xyproto/tinysvg v1.0.1
 )
+
+require github.com/xyproto/swish v1.3.0 // indirect
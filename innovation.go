@@ -0,0 +1,132 @@
+package wann
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// innovationHistory gives the same innovation number to nodes that are inserted between the same two nodes,
+// in any of the networks that share the history, as in NEAT. This makes it possible to align the nodes of
+// different networks when crossing them over. All innovation numbers are handed out by the history, so that
+// the input, output and bias nodes, inserted nodes and other new nodes never get the same number.
+type innovationHistory struct {
+	mut    sync.Mutex
+	next   int            // The next innovation number to use
+	splits map[[2]int]int // Innovation numbers of inserted nodes, by the innovation numbers of the nodes on each side
+	nodes  map[string]int // Innovation numbers of the input, output and bias nodes, by their role
+}
+
+// newInnovationHistory creates a new innovation history, where the first n innovation numbers are reserved
+func newInnovationHistory(n int) *innovationHistory {
+	return &innovationHistory{next: n, splits: make(map[[2]int]int), nodes: make(map[string]int)}
+}
+
+// reserve makes sure that the first n innovation numbers are not handed out
+func (h *innovationHistory) reserve(n int) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if h.next < n {
+		h.next = n
+	}
+}
+
+// fresh returns an innovation number that has not been handed out before
+func (h *innovationHistory) fresh() int {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	innovation := h.next
+	h.next++
+	return innovation
+}
+
+// node returns the innovation number for the input, output or bias node with the given role,
+// which is the same for all networks that share the history
+func (h *innovationHistory) node(role string) int {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	if innovation, ok := h.nodes[role]; ok {
+		return innovation
+	}
+	innovation := h.next
+	h.next++
+	h.nodes[role] = innovation
+	return innovation
+}
+
+// inputRole, outputRole and biasRole are the roles of the input, output and bias nodes in the innovation history
+func inputRole(i int) string  { return fmt.Sprintf("input %d", i) }
+func outputRole(i int) string { return fmt.Sprintf("output %d", i) }
+
+const biasRole = "bias"
+
+// split returns the innovation number for a node that is inserted between the two given nodes
+func (h *innovationHistory) split(left, right int) int {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	key := [2]int{left, right}
+	if innovation, ok := h.splits[key]; ok {
+		return innovation
+	}
+	innovation := h.next
+	h.next++
+	h.splits[key] = innovation
+	return innovation
+}
+
+// innovationSplit is how an inserted node is stored in a checkpoint
+type innovationSplit struct {
+	Left       int `json:"left"`
+	Right      int `json:"right"`
+	Innovation int `json:"innovation"`
+}
+
+// list returns all the inserted nodes, sorted by innovation number, together with the innovation numbers
+// of the input, output and bias nodes and the next innovation number
+func (h *innovationHistory) list() ([]innovationSplit, map[string]int, int) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+	splits := make([]innovationSplit, 0, len(h.splits))
+	for key, innovation := range h.splits {
+		splits = append(splits, innovationSplit{Left: key[0], Right: key[1], Innovation: innovation})
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		return splits[i].Innovation < splits[j].Innovation
+	})
+	nodes := make(map[string]int, len(h.nodes))
+	for role, innovation := range h.nodes {
+		nodes[role] = innovation
+	}
+	return splits, nodes, h.next
+}
+
+// innovationHistory returns the innovation history that is shared by all networks created with this configuration
+func (config *Config) innovationHistory() *innovationHistory {
+	if config.innovations == nil {
+		config.innovations = newInnovationHistory(0)
+	}
+	return config.innovations
+}
+
+// newInnovation returns the innovation number for a node that is inserted between the two given nodes
+func (net *Network) newInnovation(left, right NeuronIndex) int {
+	if net.innovations != nil {
+		return net.innovations.split(net.AllNodes[left].Innovation, net.AllNodes[right].Innovation)
+	}
+	return net.freshInnovation()
+}
+
+// freshInnovation returns an innovation number for a new node that is not inserted between two nodes
+func (net *Network) freshInnovation() int {
+	if net.innovations != nil {
+		return net.innovations.fresh()
+	}
+	// This network does not share an innovation history with other networks, so just use a number that is not taken
+	highest := -1
+	for _, node := range net.AllNodes {
+		if node.Innovation > highest {
+			highest = node.Innovation
+		}
+	}
+	return highest + 1
+}
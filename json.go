@@ -0,0 +1,188 @@
+package wann
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// FormatVersion is the version of the JSON format that networks are saved as
+const FormatVersion = 2
+
+// jsonNeuron is how a Neuron is stored in the JSON format
+type jsonNeuron struct {
+	ActivationFunction string        `json:"activationFunction"`
+	InputNodes         []NeuronIndex `json:"inputNodes"`
+	Innovation         *int          `json:"innovation,omitempty"` // Not stored in version 1
+}
+
+// jsonNetwork is how a Network is stored in the JSON format
+type jsonNetwork struct {
+	Version     int           `json:"version"`
+	Weight      float64       `json:"weight"`
+	InputNodes  []NeuronIndex `json:"inputNodes"`
+	OutputNodes []NeuronIndex `json:"outputNodes"`
+	Recurrent   bool          `json:"recurrent,omitempty"`
+	BiasNode    *NeuronIndex  `json:"biasNode,omitempty"`
+	Nodes       []jsonNeuron  `json:"nodes"`
+}
+
+// MarshalJSON encodes the network as JSON, storing the activation functions by name
+func (net Network) MarshalJSON() ([]byte, error) {
+	jnet := jsonNetwork{
+		Version:     FormatVersion,
+		Weight:      net.Weight,
+		InputNodes:  net.InputNodes,
+		OutputNodes: net.Outputs(),
+		Recurrent:   net.Recurrent,
+		Nodes:       make([]jsonNeuron, len(net.AllNodes)),
+	}
+	if jnet.InputNodes == nil {
+		jnet.InputNodes = []NeuronIndex{}
+	}
+	if net.Bias {
+		biasNode := net.BiasNode
+		jnet.BiasNode = &biasNode
+	}
+	for i, node := range net.AllNodes {
+		jnet.Nodes[i].ActivationFunction = node.ActivationFunction.Name()
+		innovation := node.Innovation
+		jnet.Nodes[i].Innovation = &innovation
+		jnet.Nodes[i].InputNodes = node.InputNodes
+		if jnet.Nodes[i].InputNodes == nil {
+			jnet.Nodes[i].InputNodes = []NeuronIndex{}
+		}
+	}
+	return json.Marshal(jnet)
+}
+
+// UnmarshalJSON decodes a network from JSON, checks that it is valid and
+// then updates the .Net pointers of all nodes to point to this network
+func (net *Network) UnmarshalJSON(data []byte) error {
+	var jnet jsonNetwork
+	if err := json.Unmarshal(data, &jnet); err != nil {
+		return err
+	}
+	if jnet.Version < 1 || jnet.Version > FormatVersion {
+		return fmt.Errorf("unsupported network format version: %d", jnet.Version)
+	}
+	if len(jnet.OutputNodes) == 0 {
+		return errors.New("the network has no output nodes")
+	}
+	nodeCount := NeuronIndex(len(jnet.Nodes))
+	inRange := func(ni NeuronIndex) bool {
+		return ni >= 0 && ni < nodeCount
+	}
+	allNodes := make([]Neuron, len(jnet.Nodes))
+	for i, jnode := range jnet.Nodes {
+		afi, err := ActivationFunctionByName(jnode.ActivationFunction)
+		if err != nil {
+			return err
+		}
+		allNodes[i].ActivationFunction = afi
+		allNodes[i].neuronIndex = NeuronIndex(i)
+		allNodes[i].Innovation = i
+		if jnode.Innovation != nil {
+			allNodes[i].Innovation = *jnode.Innovation
+		}
+		allNodes[i].InputNodes = make([]NeuronIndex, 0, len(jnode.InputNodes))
+		for _, inputNodeIndex := range jnode.InputNodes {
+			if !inRange(inputNodeIndex) {
+				return fmt.Errorf("node %d has an input node that is out of range: %d", i, inputNodeIndex)
+			}
+			if err := allNodes[i].AddInput(inputNodeIndex); err != nil {
+				return fmt.Errorf("node %d: %s", i, err)
+			}
+		}
+	}
+	for _, ni := range jnet.InputNodes {
+		if !inRange(ni) {
+			return fmt.Errorf("input node index is out of range: %d", ni)
+		}
+	}
+	outputNodes := make(map[NeuronIndex]bool, len(jnet.OutputNodes))
+	for _, ni := range jnet.OutputNodes {
+		if !inRange(ni) {
+			return fmt.Errorf("output node index is out of range: %d", ni)
+		}
+		if outputNodes[ni] {
+			return fmt.Errorf("output node %d is listed more than once", ni)
+		}
+		outputNodes[ni] = true
+	}
+	if jnet.BiasNode != nil {
+		if !inRange(*jnet.BiasNode) {
+			return fmt.Errorf("bias node index is out of range: %d", *jnet.BiasNode)
+		}
+		if len(allNodes[*jnet.BiasNode].InputNodes) > 0 {
+			return errors.New("the bias node has input nodes")
+		}
+		for _, ni := range append(jnet.InputNodes, jnet.OutputNodes...) {
+			if ni == *jnet.BiasNode {
+				return fmt.Errorf("the bias node is also an input or output node: %d", ni)
+			}
+		}
+		allNodes[*jnet.BiasNode].SetValue(1.0)
+	}
+	net.AllNodes = allNodes
+	net.InputNodes = jnet.InputNodes
+	net.OutputNodes = jnet.OutputNodes
+	net.OutputNode = jnet.OutputNodes[0]
+	net.Weight = jnet.Weight
+	net.Recurrent = jnet.Recurrent
+	net.Bias = jnet.BiasNode != nil
+	net.BiasNode = 0
+	if net.Bias {
+		net.BiasNode = *jnet.BiasNode
+	}
+	net.UpdateNetworkPointers()
+	if !net.Recurrent && net.hasCycle() {
+		return errors.New("the network has a cycle, but is not recurrent")
+	}
+	return nil
+}
+
+// Save saves the network as a JSON file
+func (net *Network) Save(filename string) error {
+	data, err := json.MarshalIndent(net, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// Load loads a network from a JSON file
+func Load(filename string) (*Network, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var net Network
+	if err := json.Unmarshal(data, &net); err != nil {
+		return nil, err
+	}
+	return &net, nil
+}
+
+// LoadNetwork loads a network from a JSON file, and checks that it only uses the activation functions in
+// config.AllowedActivationFunctions, if set. The network then uses the pseudo-random number generator,
+// complexity measure and allowed activation functions of this configuration, when it is modified and scored.
+func (config *Config) LoadNetwork(filename string) (*Network, error) {
+	net, err := Load(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.checkActivationFunctions(net); err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	net.SetRand(config.Rand)
+	net.innovations = config.innovationHistory()
+	// Do not hand out the innovation numbers of the loaded nodes to new nodes
+	for _, node := range net.AllNodes {
+		net.innovations.reserve(node.Innovation + 1)
+	}
+	net.complexity = config.complexity()
+	net.allowed = config.AllowedActivationFunctions
+	return net, nil
+}
@@ -0,0 +1,83 @@
+package wann
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 5,
+		Outputs:                2,
+		InitialConnectionRatio: 0.7,
+		sharedWeight:           0.5,
+	})
+	net.UpdateNetworkPointers()
+	for i := 0; i < 10; i++ {
+		net.Modify(10)
+	}
+	if err := net.Save("test.json"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("test.json")
+	loaded, err := Load("test.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != net.String() {
+		t.Errorf("the loaded network differs from the saved network:\n%s\n%s", loaded, net)
+	}
+	for _, node := range loaded.AllNodes {
+		if node.Net != loaded {
+			t.Fatal("the .Net pointer of a loaded node does not point to the loaded network")
+		}
+	}
+	inputValues := []float64{0.1, 0.2, 0.3, 0.4, 0.5}
+	a, b := net.EvaluateAll(inputValues), loaded.EvaluateAll(inputValues)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("output %d differs: %f != %f", i, a[i], b[i])
+		}
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"version": 999, "weight": 1, "inputNodes": [], "outputNodes": [0], "nodes": [{"activationFunction": "Linear", "inputNodes": []}]}`,
+		`{"version": 1, "weight": 1, "inputNodes": [], "outputNodes": [], "nodes": [{"activationFunction": "Linear", "inputNodes": []}]}`,
+		`{"version": 1, "weight": 1, "inputNodes": [], "outputNodes": [0], "nodes": [{"activationFunction": "Unknown", "inputNodes": []}]}`,
+		`{"version": 1, "weight": 1, "inputNodes": [], "outputNodes": [0], "nodes": [{"activationFunction": "Linear", "inputNodes": [7]}]}`,
+		`{"version": 1, "weight": 1, "inputNodes": [3], "outputNodes": [0], "nodes": [{"activationFunction": "Linear", "inputNodes": []}]}`,
+		`{"version": 1, "weight": 1, "inputNodes": [1], "outputNodes": [0, 0], "nodes": [{"activationFunction": "Linear", "inputNodes": [1]}, {"activationFunction": "Linear", "inputNodes": []}]}`,
+		`{"version": 2, "weight": 1, "inputNodes": [1], "outputNodes": [0], "nodes": [{"activationFunction": "Linear", "inputNodes": [2]}, {"activationFunction": "Linear", "inputNodes": []}, {"activationFunction": "Linear", "inputNodes": [1, 3]}, {"activationFunction": "Linear", "inputNodes": [2]}]}`,
+		`{"version": 2, "weight": 1, "inputNodes": [], "outputNodes": [0], "biasNode": 0, "nodes": [{"activationFunction": "Linear", "inputNodes": []}]}`,
+		`{"version": 2, "weight": 1, "inputNodes": [1], "outputNodes": [0], "biasNode": 1, "nodes": [{"activationFunction": "Linear", "inputNodes": [1]}, {"activationFunction": "Linear", "inputNodes": []}]}`,
+	} {
+		var net Network
+		if err := json.Unmarshal([]byte(data), &net); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
+
+func TestLoadNetworkAllowed(t *testing.T) {
+	net := newIdentityNetwork(2)
+	net.AllNodes[net.OutputNodes[0]].ActivationFunction = Gauss
+	// The activation functions of the input nodes are never used, so they are not checked
+	net.AllNodes[net.InputNodes[0]].ActivationFunction = Sigmoid
+	if err := net.Save("test_allowed.json"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("test_allowed.json")
+	config := &Config{AllowedActivationFunctions: []ActivationFunctionIndex{Linear, Gauss}}
+	if _, err := config.LoadNetwork("test_allowed.json"); err != nil {
+		t.Fatal(err)
+	}
+	config.AllowedActivationFunctions = []ActivationFunctionIndex{Linear, ReLU}
+	if _, err := config.LoadNetwork("test_allowed.json"); err == nil {
+		t.Error("expected an error when loading a network with an activation function that is not allowed")
+	}
+}
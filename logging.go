@@ -0,0 +1,39 @@
+package wann
+
+import (
+	"context"
+	"log/slog"
+	"os"
+)
+
+// discardHandler is a slog.Handler that drops all log records
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// silentLogger is used when no logger is configured
+var silentLogger = slog.New(discardHandler{})
+
+// initLogger creates the logger that writes to stdout, if config.Verbose is set and no logger is configured,
+// so that it is only created once
+func (config *Config) initLogger() {
+	if config.Logger == nil && config.Verbose && config.verboseLogger == nil {
+		config.verboseLogger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+}
+
+// logger returns the configured logger. If config.Logger is nil, all messages are
+// written to stdout if config.Verbose is set, or discarded if it is not.
+func (config *Config) logger() *slog.Logger {
+	if config.Logger != nil {
+		return config.Logger
+	}
+	if config.Verbose {
+		config.initLogger()
+		return config.verboseLogger
+	}
+	return silentLogger
+}
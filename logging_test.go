@@ -0,0 +1,66 @@
+package wann
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            3,
+		PopulationSize:         20,
+		RandomSeed:             commonSeed,
+		Logger:                 slog.New(slog.NewJSONHandler(&buf, nil)),
+	}
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0},
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	generations := 0
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record map[string]interface{}
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		if record["msg"] == "using random seed" && record["seed"] != float64(commonSeed) {
+			t.Errorf("wrong seed in log record: %v", record)
+		}
+		if record["msg"] == "generation" {
+			if _, ok := record["bestScore"]; !ok {
+				t.Errorf("no best score in log record: %v", record)
+			}
+			generations++
+		}
+	}
+	if generations != config.Generations {
+		t.Errorf("expected %d generation log records, got %d", config.Generations, generations)
+	}
+}
+
+func TestSilentLogger(t *testing.T) {
+	config := &Config{}
+	if config.logger().Enabled(context.Background(), slog.LevelError) {
+		t.Error("expected nothing to be logged by default")
+	}
+}
+
+func TestVerboseLogger(t *testing.T) {
+	config := &Config{Verbose: true, RandomSeed: commonSeed}
+	config.Init()
+	logger := config.logger()
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("expected debug messages to be logged when Verbose is set")
+	}
+	if config.logger() != logger {
+		t.Error("expected the same logger to be used every time")
+	}
+}
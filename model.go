@@ -0,0 +1,93 @@
+package wann
+
+import (
+	"sync"
+)
+
+// Model is a frozen, read-only version of a trained network, made by Network.Model.
+// Unlike a Network, a Model can be evaluated by many goroutines at the same time, since the state
+// that is needed for each evaluation is kept outside of the model. Modifying the network that the
+// model was made from does not change the model.
+type Model struct {
+	plan   *CompiledNetwork
+	weight float64
+	// Buffers for the result of each step, so that evaluating does not allocate memory
+	buffers sync.Pool
+}
+
+// Model creates a read-only model from a copy of the network, using the current shared weight
+func (net *Network) Model() *Model {
+	plan := net.Copy().Compile()
+	m := &Model{plan: plan, weight: net.Weight}
+	m.buffers.New = func() any {
+		values := make([]float64, len(plan.steps))
+		return &values
+	}
+	return m
+}
+
+// Inputs returns the number of input values that the model expects
+func (m *Model) Inputs() int {
+	return m.plan.inputs
+}
+
+// Outputs returns the number of output values that the model gives
+func (m *Model) Outputs() int {
+	return len(m.plan.outputs)
+}
+
+// Weight returns the shared weight of the model
+func (m *Model) Weight() float64 {
+	return m.weight
+}
+
+// Evaluate returns the value of the first output node, like CompiledNetwork.Evaluate.
+// Returns an error if there is not exactly one input value per input node.
+func (m *Model) Evaluate(inputValues []float64) (float64, error) {
+	if err := m.plan.checkInputValues(inputValues); err != nil {
+		return 0.0, err
+	}
+	values := m.buffers.Get().(*[]float64)
+	defer m.buffers.Put(values)
+	m.plan.run(*values, m.plan.ends[0], inputValues, m.weight)
+	return m.plan.result(*values, 0), nil
+}
+
+// EvaluateAll evaluates the model for the given input values, and stores one result per output node in results,
+// which is returned. A new slice is only allocated if results is too short. See also Evaluate.
+func (m *Model) EvaluateAll(inputValues []float64, results []float64) ([]float64, error) {
+	if err := m.plan.checkInputValues(inputValues); err != nil {
+		return nil, err
+	}
+	if len(results) < len(m.plan.outputs) {
+		results = make([]float64, len(m.plan.outputs))
+	}
+	values := m.buffers.Get().(*[]float64)
+	defer m.buffers.Put(values)
+	m.plan.run(*values, len(m.plan.steps), inputValues, m.weight)
+	for i := range m.plan.outputs {
+		results[i] = m.plan.result(*values, i)
+	}
+	return results[:len(m.plan.outputs)], nil
+}
+
+// Classify returns the index of the output node with the largest value, which is the class label
+func (m *Model) Classify(inputValues []float64) (int, error) {
+	results, err := m.EvaluateAll(inputValues, make([]float64, len(m.plan.outputs)))
+	if err != nil {
+		return 0, err
+	}
+	return Argmax(results), nil
+}
+
+// EvaluateBatch evaluates the model for each row of input values, and returns the value of the first
+// output node for each row. Returns an error if a row does not have one value per input node.
+func (m *Model) EvaluateBatch(inputs [][]float64) ([]float64, error) {
+	if err := m.plan.checkRows(inputs); err != nil {
+		return nil, err
+	}
+	results := make([]float64, len(inputs))
+	values := m.plan.runBatch(m.plan.ends[0], len(inputs), inputs, nil, m.weight)
+	m.plan.batchResults(values, 0, len(inputs), results)
+	return results, nil
+}
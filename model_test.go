@@ -0,0 +1,89 @@
+package wann
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestModel(t *testing.T) {
+	config := &Config{
+		inputs:                 6,
+		Outputs:                3,
+		InitialConnectionRatio: 0.5,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for i := 0; i < 20; i++ {
+		net.Modify(10)
+	}
+	net.SetWeight(0.5)
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{-0.5, 0.25, 1.0, 0.75, 0.0, -1.0},
+	}
+	expected := make([]float64, len(inputData))
+	expectedAll := make([][]float64, len(inputData))
+	for row, inputValues := range inputData {
+		expected[row] = net.Evaluate(inputValues)
+		expectedAll[row] = net.EvaluateAll(inputValues)
+	}
+	model := net.Model()
+	// Changing the network must not change the model
+	net.SetWeight(2.0)
+	net.Modify(10)
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results := make([]float64, model.Outputs())
+			for i := 0; i < 100; i++ {
+				for row, inputValues := range inputData {
+					if result, err := model.Evaluate(inputValues); err != nil || result != expected[row] {
+						errs <- "Evaluate gave a different result"
+						return
+					}
+					var err error
+					if results, err = model.EvaluateAll(inputValues, results); err != nil {
+						errs <- err.Error()
+						return
+					}
+					for j := range results {
+						if results[j] != expectedAll[row][j] {
+							errs <- "EvaluateAll gave a different result"
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	batch, err := model.EvaluateBatch(inputData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for row, result := range batch {
+		if result != expected[row] {
+			t.Errorf("row %d: expected %v, got %v", row, expected[row], result)
+		}
+	}
+	// There must be one input value per input node, as for a compiled network
+	if _, err := model.Evaluate([]float64{1.0}); err == nil {
+		t.Error("expected an error for missing input values")
+	}
+	if _, err := model.Classify(append(inputData[0], 1.0)); err == nil {
+		t.Error("expected an error for extra input values")
+	}
+	if _, err := model.EvaluateBatch([][]float64{inputData[0], {1.0}}); err == nil {
+		t.Error("expected an error for a row with missing input values")
+	}
+}
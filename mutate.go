@@ -0,0 +1,192 @@
+package wann
+
+// MutationRates are the relative probabilities of each way of modifying a network, when evolving.
+// The rates do not need to sum up to 1. If they are all 0, the first three are used with equal probability,
+// as in the paper. The last three operators can remove nodes and connections, so that networks can also shrink.
+type MutationRates struct {
+	InsertNode               float64 // Insert a node, replacing an existing connection
+	AddConnection            float64 // Add a connection between two nodes
+	ChangeActivationFunction float64 // Change the activation function of a node
+	RemoveConnection         float64 // Remove a connection
+	RemoveNode               float64 // Remove a node that is neither an input nor an output node, and all its connections
+	RewireConnection         float64 // Connect another node instead of the input node of a connection
+}
+
+// DefaultMutationRates only insert nodes, add connections and change activation functions, with equal probability
+var DefaultMutationRates = MutationRates{InsertNode: 1.0, AddConnection: 1.0, ChangeActivationFunction: 1.0}
+
+// rates returns the mutation rates as a slice, in the same order as the methods of Network.mutate
+func (rates MutationRates) rates() []float64 {
+	return []float64{rates.InsertNode, rates.AddConnection, rates.ChangeActivationFunction, rates.RemoveConnection, rates.RemoveNode, rates.RewireConnection}
+}
+
+// ModifyWith modifies the network using one of the six mutation operators, chosen with the given relative probabilities
+func (net *Network) ModifyWith(rates MutationRates, maxIterations int) {
+	weights := rates.rates()
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		net.Modify(maxIterations)
+		return
+	}
+	x := net.random().Float64() * total
+	method := 0
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		method = i
+		if x < w {
+			break
+		}
+		x -= w
+	}
+	net.mutate(method, maxIterations)
+}
+
+// RemoveRandomConnection removes a random connection. The last input connection of an output node is never removed.
+// Returns false if there were no connections that could be removed.
+func (net *Network) RemoveRandomConnection() bool {
+	type connection struct{ from, to NeuronIndex }
+	var connections []connection
+	for i, node := range net.AllNodes {
+		if net.IsOutput(NeuronIndex(i)) && len(node.InputNodes) < 2 {
+			continue
+		}
+		for _, inputNodeIndex := range node.InputNodes {
+			connections = append(connections, connection{inputNodeIndex, NeuronIndex(i)})
+		}
+	}
+	if len(connections) == 0 {
+		return false
+	}
+	c := connections[net.random().Intn(len(connections))]
+	if err := net.AllNodes[c.to].RemoveInput(c.from); err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// RemoveRandomHiddenNode removes a random node that is neither an input, output nor bias node, together with all
+// connections to and from it. The nodes after it in net.AllNodes get a neuron index that is one lower.
+// Returns false if there were no such nodes.
+func (net *Network) RemoveRandomHiddenNode() bool {
+	var hidden []NeuronIndex
+	for i := range net.AllNodes {
+		if ni := NeuronIndex(i); !net.IsInput(ni) && !net.IsOutput(ni) && !net.IsBias(ni) {
+			hidden = append(hidden, ni)
+		}
+	}
+	if len(hidden) == 0 {
+		return false
+	}
+	net.removeHiddenNode(hidden[net.random().Intn(len(hidden))])
+	// Update the distances from the output nodes, since paths may have become shorter
+	net.Connected()
+	return true
+}
+
+// removeHiddenNode removes the given hidden node. The hidden nodes that only had the removed node as input
+// get the input nodes of the removed node instead, or are also removed, if there are none.
+func (net *Network) removeHiddenNode(removed NeuronIndex) {
+	queue := []NeuronIndex{removed}
+	for len(queue) > 0 {
+		removed, queue = queue[0], queue[1:]
+		for i := range net.AllNodes {
+			ni := NeuronIndex(i)
+			node := &net.AllNodes[i]
+			if ni == removed || net.IsOutput(ni) || len(node.InputNodes) != 1 || node.InputNodes[0] != removed {
+				continue
+			}
+			// Connecting the nodes on each side of the removed node does not create a new cycle
+			for _, inputNodeIndex := range net.AllNodes[removed].InputNodes {
+				_ = node.AddInput(inputNodeIndex) // Fails only if the node would be its own input
+			}
+			if len(node.InputNodes) == 1 {
+				queue = append(queue, ni)
+			}
+		}
+		net.removeNode(removed)
+		for i, ni := range queue {
+			if ni > removed {
+				queue[i] = ni - 1
+			}
+		}
+	}
+}
+
+// removeNode removes the given node and all connections to it, and updates the neuron indices that come after it
+func (net *Network) removeNode(removed NeuronIndex) {
+	renumber := func(ni NeuronIndex) NeuronIndex {
+		if ni > removed {
+			return ni - 1
+		}
+		return ni
+	}
+	net.AllNodes = append(net.AllNodes[:removed], net.AllNodes[removed+1:]...)
+	for i := range net.AllNodes {
+		node := &net.AllNodes[i]
+		node.neuronIndex = NeuronIndex(i)
+		inputNodes := node.InputNodes[:0]
+		for _, inputNodeIndex := range node.InputNodes {
+			if inputNodeIndex != removed {
+				inputNodes = append(inputNodes, renumber(inputNodeIndex))
+			}
+		}
+		node.InputNodes = inputNodes
+	}
+	for i, inputNodeIndex := range net.InputNodes {
+		net.InputNodes[i] = renumber(inputNodeIndex)
+	}
+	for i, outputNodeIndex := range net.OutputNodes {
+		net.OutputNodes[i] = renumber(outputNodeIndex)
+	}
+	net.OutputNode = renumber(net.OutputNode)
+	net.BiasNode = renumber(net.BiasNode)
+}
+
+// RewireRandomConnection replaces the input node of a random connection with another random node,
+// as long as this does not create a cycle, unless the network is recurrent. Returns false if no connection could be rewired.
+func (net *Network) RewireRandomConnection() bool {
+	type connection struct{ from, to NeuronIndex }
+	var connections []connection
+	for i, node := range net.AllNodes {
+		for _, inputNodeIndex := range node.InputNodes {
+			connections = append(connections, connection{inputNodeIndex, NeuronIndex(i)})
+		}
+	}
+	if len(connections) == 0 {
+		return false
+	}
+	c := connections[net.random().Intn(len(connections))]
+	// Try the other nodes in a random order
+	for _, i := range net.random().Perm(len(net.AllNodes)) {
+		from := NeuronIndex(i)
+		if from == c.from || from == c.to || net.AllNodes[c.to].HasInput(from) || (!net.Recurrent && (net.IsOutput(from) || net.dependsOn(from, c.to))) {
+			continue
+		}
+		if err := net.AllNodes[c.to].RemoveInput(c.from); err != nil {
+			panic(err)
+		}
+		if err := net.AllNodes[c.to].AddInput(from); err != nil {
+			panic(err)
+		}
+		return true
+	}
+	return false
+}
+
+// modify modifies the network using config.MutationRates, config.MutationsPerOffspring times
+func (config *Config) modify(net *Network, maxIterations int) {
+	n := config.MutationsPerOffspring
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		net.ModifyWith(config.MutationRates, maxIterations)
+	}
+}
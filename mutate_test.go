@@ -0,0 +1,159 @@
+package wann
+
+import (
+	"testing"
+)
+
+func TestRemoveRandomHiddenNode(t *testing.T) {
+	config := &Config{
+		inputs:                 5,
+		Outputs:                2,
+		InitialConnectionRatio: 0.7,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	if net.RemoveRandomHiddenNode() {
+		t.Fatal("expected no hidden nodes in a new network")
+	}
+	for i := 0; i < 20; i++ {
+		net.Modify(10)
+	}
+	before := len(net.AllNodes)
+	if before == len(net.InputNodes)+len(net.OutputNodes) {
+		t.Fatal("expected the modified network to have hidden nodes")
+	}
+	if !net.RemoveRandomHiddenNode() {
+		t.Fatal("could not remove a hidden node")
+	}
+	if len(net.AllNodes) > before-1 {
+		t.Errorf("expected at most %d nodes, got %d", before-1, len(net.AllNodes))
+	}
+	for i, node := range net.AllNodes {
+		if node.neuronIndex != NeuronIndex(i) {
+			t.Errorf("expected neuron index %d, got %d", i, node.neuronIndex)
+		}
+		if !node.InputNeuronsAreGood() {
+			t.Errorf("node %d has input nodes that do not exist", i)
+		}
+	}
+	for _, outputNodeIndex := range net.OutputNodes {
+		if net.IsInput(outputNodeIndex) || int(outputNodeIndex) >= len(net.AllNodes) {
+			t.Errorf("invalid output node %d", outputNodeIndex)
+		}
+	}
+	// The distances from the output nodes are up to date
+	fresh := net.Copy()
+	fresh.Connected()
+	for i := range net.AllNodes {
+		if net.AllNodes[i].distanceFromOutputNode != fresh.AllNodes[i].distanceFromOutputNode {
+			t.Errorf("node %d has a distance from the output nodes that is not up to date", i)
+		}
+	}
+	net.EvaluateAll([]float64{0.1, 0.2, 0.3, 0.4, 0.5})
+}
+
+func TestRemoveHiddenNode(t *testing.T) {
+	// input -> a -> b -> output
+	net := NewNetwork(&Config{inputs: 1})
+	net.UpdateNetworkPointers()
+	in, out := net.InputNodes[0], net.OutputNode
+	_, a := net.NewNeuron()
+	_, b := net.NewNeuron()
+	net.AllNodes[a].AddInput(in)
+	net.AllNodes[b].AddInput(a)
+	net.AllNodes[out].AddInput(b)
+
+	// b is connected to the input node instead
+	removed := net.Copy()
+	removed.removeHiddenNode(a)
+	if len(removed.AllNodes) != 3 || !removed.AllNodes[b-1].HasInput(in) {
+		t.Errorf("expected the node after the removed node to get its input nodes, got %v", removed.AllNodes)
+	}
+
+	// b is also removed, when a has no input nodes
+	net.AllNodes[a].RemoveInput(in)
+	net.removeHiddenNode(a)
+	if len(net.AllNodes) != 2 || len(net.AllNodes[out].InputNodes) != 0 {
+		t.Errorf("expected the node after the removed node to also be removed, got %v", net.AllNodes)
+	}
+}
+
+func TestInsertRandomNodeWithoutInputs(t *testing.T) {
+	config := &Config{
+		inputs:                 1,
+		InitialConnectionRatio: 1.0,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for !net.InsertRandomNode() {
+	}
+	// Remove the input connection of the new hidden node, which is still connected to the output node
+	hidden := net.AllNodes[net.OutputNode].InputNodes[0]
+	if err := net.AllNodes[hidden].RemoveInput(net.InputNodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(net.AllNodes[hidden].InputNodes) != 0 {
+		t.Fatal("expected the hidden node to have no input nodes")
+	}
+	for i := 0; i < 20; i++ {
+		net.InsertRandomNode()
+	}
+}
+
+func TestModifyWith(t *testing.T) {
+	config := &Config{
+		inputs:                 5,
+		InitialConnectionRatio: 0.7,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for i := 0; i < 20; i++ {
+		net.Modify(10)
+	}
+	// Only removing nodes and connections should shrink the network
+	nodes := len(net.AllNodes)
+	shrink := MutationRates{RemoveConnection: 1.0, RemoveNode: 1.0, RewireConnection: 1.0}
+	for i := 0; i < 50; i++ {
+		net.ModifyWith(shrink, 10)
+	}
+	if len(net.AllNodes) >= nodes {
+		t.Errorf("expected fewer than %d nodes, got %d", nodes, len(net.AllNodes))
+	}
+	if len(net.AllNodes[net.OutputNode].InputNodes) == 0 {
+		t.Error("expected the output node to keep at least one input node")
+	}
+	net.Evaluate([]float64{0.1, 0.2, 0.3, 0.4, 0.5})
+}
+
+func TestEvolveMutationRates(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         40,
+		RandomSeed:             commonSeed,
+		MutationRates: MutationRates{
+			InsertNode:               1.0,
+			AddConnection:            1.0,
+			ChangeActivationFunction: 1.0,
+			RemoveConnection:         0.5,
+			RemoveNode:               0.5,
+			RewireConnection:         0.5,
+		},
+		MutationsPerOffspring: 2,
+		CrossoverRate:         0.25,
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -11,15 +11,24 @@ import (
 // NeuronIndex is an index into the AllNodes slice
 type NeuronIndex int
 
-// Network is a collection of nodes, an output node and a shared weight.
+// Network is a collection of nodes, one or more output nodes and a shared weight.
 type Network struct {
-	AllNodes   []Neuron      // Storing the actual neurons
-	InputNodes []NeuronIndex // Pointers to the input nodes
-	OutputNode NeuronIndex   // Pointer to the output node
-	Weight     float64       // Shared weight
+	AllNodes    []Neuron      // Storing the actual neurons
+	InputNodes  []NeuronIndex // Pointers to the input nodes
+	OutputNode  NeuronIndex   // Pointer to the first output node
+	OutputNodes []NeuronIndex // Pointers to all output nodes, starting with OutputNode
+	Weight      float64       // Shared weight
+	Recurrent   bool          // Allow connections that form cycles, see Step
+	Bias        bool          // Has a bias node, which always has the value 1
+	BiasNode    NeuronIndex   // Pointer to the bias node, if Bias is set
+	state       []float64     // The value of each node after the last time step, see Step
+	rng         *rand.Rand    // Pseudo-random number generator, used when mutating
+	innovations *innovationHistory
+	complexity  ComplexityFunc            // Used by Complexity, if set
+	allowed     []ActivationFunctionIndex // The activation functions that may be chosen when mutating, all if empty
 }
 
-// NewNetwork creates a new minimal network with n input nodes and ratio of r connections.
+// NewNetwork creates a new minimal network with n input nodes, m output nodes and ratio of r connections.
 // Passing "nil" as an argument is supported.
 func NewNetwork(cs ...*Config) Network {
 	c := &Config{}
@@ -28,33 +37,53 @@ func NewNetwork(cs ...*Config) Network {
 		c = cs[0]
 	}
 	n := c.inputs
+	m := c.outputs()
 	r := c.InitialConnectionRatio
 	w := c.sharedWeight
-	// Create a new network that has one node, the output node
-	outputNodeIndex := NeuronIndex(0)
-	net := Network{make([]Neuron, 0, n+1), make([]NeuronIndex, n), outputNodeIndex, w}
-	outputNode, outputNodeIndex := net.NewNeuron()
-	net.OutputNode = outputNodeIndex
-
-	// Initialize n input nodes that all are inputs to the one output node.
+	// Create a new network that has m nodes, the output nodes
+	net := Network{
+		AllNodes:    make([]Neuron, 0, n+m),
+		InputNodes:  make([]NeuronIndex, n),
+		OutputNodes: make([]NeuronIndex, m),
+		Weight:      w,
+		Recurrent:   c.Recurrent,
+		Bias:        c.Bias,
+		rng:         c.Rand,
+		innovations: c.innovationHistory(),
+		complexity:  c.complexity(),
+		allowed:     c.AllowedActivationFunctions,
+	}
+	// The input, output and bias nodes get the same innovation numbers in all networks of this configuration
+	for i := 0; i < m; i++ {
+		_, outputNodeIndex := net.newNeuron(net.innovations.node(outputRole(i)))
+		net.OutputNodes[i] = outputNodeIndex
+	}
+	net.OutputNode = net.OutputNodes[0]
+
+	// Add a bias node that may be an input to the output nodes, in the same way as the input nodes.
+	// It is added first, so that it is evaluated before the input nodes.
+	if net.Bias {
+		net.addBiasNode(r)
+	}
+
+	// Initialize n input nodes that all may be inputs to the output nodes.
 	for i := 0; i < n; i++ {
 		// Add a new input node
-		_, nodeIndex := net.NewNeuron()
+		_, nodeIndex := net.newNeuron(net.innovations.node(inputRole(i)))
 
 		// Register the input node index in the input node NeuronIndex slice
 		net.InputNodes[i] = nodeIndex
 
 		// Make connections for all nodes where a random number between 0 and 1 are larger than r
-		if r >= rand.Float64() {
-			if err := outputNode.AddInput(nodeIndex); err != nil {
-				panic(err)
+		for _, outputNodeIndex := range net.OutputNodes {
+			if r >= net.random().Float64() {
+				if err := net.AllNodes[outputNodeIndex].AddInput(nodeIndex); err != nil {
+					panic(err)
+				}
 			}
 		}
 	}
 
-	// Store the modified output node
-	net.AllNodes[outputNodeIndex] = *outputNode
-
 	return net
 }
 
@@ -73,6 +102,25 @@ func (net *Network) IsInput(ni NeuronIndex) bool {
 	return false
 }
 
+// Outputs returns the output nodes of this network.
+// Networks that only have the OutputNode field set are also supported.
+func (net *Network) Outputs() []NeuronIndex {
+	if len(net.OutputNodes) == 0 {
+		return []NeuronIndex{net.OutputNode}
+	}
+	return net.OutputNodes
+}
+
+// IsOutput checks if the given node is an output node
+func (net *Network) IsOutput(ni NeuronIndex) bool {
+	for _, outputNodeIndex := range net.Outputs() {
+		if ni == outputNodeIndex {
+			return true
+		}
+	}
+	return false
+}
+
 //
 // Operators for searching the space of network topologies
 //
@@ -99,7 +147,7 @@ func (net *Network) InsertNode(a, b NeuronIndex, newNodeIndex NeuronIndex) error
 	}
 
 	// This should never happen
-	if a == net.OutputNode {
+	if net.IsOutput(a) {
 		panic("implementation error: the leftmost node is an output node and this was not cought earlier")
 	}
 
@@ -136,11 +184,15 @@ func (net *Network) InsertNode(a, b NeuronIndex, newNodeIndex NeuronIndex) error
 
 // AddConnection adds a connection from a to b.
 // The order is swapped if needed, then a is added as an input to b.
+// If the network is recurrent, the order is kept, so that the connection may form a cycle.
 func (net *Network) AddConnection(a, b NeuronIndex) error {
 	lastIndex := NeuronIndex(len(net.AllNodes) - 1)
 	if a < 0 || a > lastIndex || b < 0 || b > lastIndex {
 		return errors.New("index out of range")
 	}
+	if net.Recurrent {
+		return net.addRecurrentConnection(a, b)
+	}
 	// Sort the nodes by where they place in the diagram
 	var arbitrary bool
 	a, b, arbitrary = net.LeftRight(a, b)
@@ -151,7 +203,7 @@ func (net *Network) AddConnection(a, b NeuronIndex) error {
 		return errors.New("error: arbitrary ordering when adding a connection")
 	}
 	// a should not be an output node
-	if a == net.OutputNode {
+	if net.IsOutput(a) {
 		return errors.New("error: will not insert a node between the output node and another node")
 	}
 	// b should not be an input node
@@ -171,25 +223,92 @@ func (net *Network) AddConnection(a, b NeuronIndex) error {
 // RandomizeActivationFunctionForRandomNeuron randomizes the activation function for a randomly selected neuron
 func (net *Network) RandomizeActivationFunctionForRandomNeuron() {
 	chosenNeuronIndex := net.GetRandomNode()
-	chosenActivationFunctionIndex := ActivationFunctionIndex(rand.Intn(len(ActivationFunctions)))
+	chosenActivationFunctionIndex := net.randomActivationFunction()
 	net.AllNodes[chosenNeuronIndex].ActivationFunction = chosenActivationFunctionIndex
 }
 
-// Evaluate will return a weighted sum of the input nodes,
-// using the .Value field if it is set and no input nodes are available.
-// A shared weight can be given.
-func (net *Network) Evaluate(inputValues []float64) float64 {
+// setInputValues assigns the given values to the .Value field of the network input nodes
+func (net *Network) setInputValues(inputValues []float64) {
 	inputLength := len(inputValues)
 	for i, nindex := range net.InputNodes {
 		if i < inputLength {
 			net.AllNodes[nindex].SetValue(inputValues[i])
 		}
 	}
-	maxIterationCounter := inputLength
-	result, _ := net.AllNodes[net.OutputNode].evaluate(net.Weight, &maxIterationCounter)
+}
+
+// Evaluate will return a weighted sum of the input nodes,
+// using the .Value field if it is set and no input nodes are available.
+// A shared weight can be given.
+// Only the value of the first output node is returned, see EvaluateAll.
+// Each connected node is evaluated once, in the same order as for Compile, which gives the same results.
+func (net *Network) Evaluate(inputValues []float64) float64 {
+	net.setInputValues(inputValues)
+	values := make([]float64, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	result, _ := net.evaluateNode(net.OutputNode, values, states)
 	return result
 }
 
+// EvaluateAll will evaluate the network for the given input values
+// and return one result per output node. The nodes that several output nodes depend on are evaluated once.
+func (net *Network) EvaluateAll(inputValues []float64) []float64 {
+	net.setInputValues(inputValues)
+	values := make([]float64, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	outputs := net.Outputs()
+	results := make([]float64, len(outputs))
+	for i, outputNodeIndex := range outputs {
+		results[i], _ = net.evaluateNode(outputNodeIndex, values, states)
+	}
+	return results
+}
+
+// evaluateNode returns the result of the given node, after evaluating its input nodes depth first, and stores it in
+// values. Each node is evaluated once, see Step for the states. A connection that closes a cycle uses 0, as for the
+// first Step after Reset. A node without input nodes has the value of its .Value field, if it is set and this is not
+// an output node. Otherwise, the node has no result, which counts as 0, and false is returned. This is the same as
+// for Compile, so that the results are the same.
+func (net *Network) evaluateNode(nodeIndex NeuronIndex, values []float64, states []int) (float64, bool) {
+	switch states[nodeIndex] {
+	case nodeVisited:
+		return values[nodeIndex], true
+	case nodeEmpty:
+		return 0.0, false
+	}
+	states[nodeIndex] = nodeVisiting
+	neuron := &net.AllNodes[nodeIndex]
+	summed := 0.0
+	counter := 0
+	for _, inputNeuronIndex := range neuron.InputNodes {
+		if int(inputNeuronIndex) >= len(net.AllNodes) {
+			continue
+		}
+		counter++
+		if states[inputNeuronIndex] == nodeVisiting {
+			// This connection closes a cycle, and uses 0.0
+			continue
+		}
+		if result, ok := net.evaluateNode(inputNeuronIndex, values, states); ok {
+			summed += result * net.Weight
+		}
+	}
+	switch {
+	case counter == 0 && neuron.Value != nil && !neuron.IsOutput():
+		values[nodeIndex] = *(neuron.Value)
+	case counter == 0 && !neuron.IsOutput() && net.IsInput(nodeIndex):
+		// An input node that has not been given a value
+		values[nodeIndex] = 0.0
+	case counter == 0:
+		states[nodeIndex] = nodeEmpty
+		return 0.0, false
+	default:
+		values[nodeIndex] = neuron.GetActivationFunction()(summed)
+	}
+	states[nodeIndex] = nodeVisited
+	return values[nodeIndex], true
+}
+
 // SetWeight will set a shared weight for the entire network
 func (net *Network) SetWeight(weight float64) {
 	net.Weight = weight
@@ -205,13 +324,15 @@ func (net *Network) LeftRight(a, b NeuronIndex) (NeuronIndex, NeuronIndex, bool)
 		return a, b, true // Arbitrary order
 	}
 	// First check the network output nodes
-	if a == net.OutputNode && b == net.OutputNode {
+	aIsNetworkOutputNode := net.IsOutput(a)
+	bIsNetworkOutputNode := net.IsOutput(b)
+	if aIsNetworkOutputNode && bIsNetworkOutputNode {
 		return a, b, true // Arbitrary order
 	}
-	if a == net.OutputNode && b != net.OutputNode {
+	if aIsNetworkOutputNode && !bIsNetworkOutputNode {
 		return b, a, false // Swap order
 	}
-	if a != net.OutputNode && b == net.OutputNode {
+	if !aIsNetworkOutputNode && bIsNetworkOutputNode {
 		return a, b, false // Same order
 	}
 	// Then check if the nodes are already connected
@@ -222,8 +343,8 @@ func (net *Network) LeftRight(a, b NeuronIndex) (NeuronIndex, NeuronIndex, bool)
 		return b, a, false // Swap order
 	}
 	// Then check the input nodes of the network
-	aIsNetworkInputNode := net.AllNodes[a].In(net.InputNodes)
-	bIsNetworkInputNode := net.AllNodes[b].In(net.InputNodes)
+	aIsNetworkInputNode := net.AllNodes[a].In(net.InputNodes) || net.IsBias(a)
+	bIsNetworkInputNode := net.AllNodes[b].In(net.InputNodes) || net.IsBias(b)
 	if aIsNetworkInputNode && !bIsNetworkInputNode {
 		return a, b, false // Same order
 	}
@@ -267,12 +388,12 @@ func (net *Network) All() []*Neuron {
 // GetRandomNode will select a random neuron.
 // This can be any node, including the output node.
 func (net *Network) GetRandomNode() NeuronIndex {
-	return NeuronIndex(rand.Intn(len(net.AllNodes)))
+	return NeuronIndex(net.random().Intn(len(net.AllNodes)))
 }
 
 // GetRandomInputNode returns a random input node
 func (net *Network) GetRandomInputNode() NeuronIndex {
-	inputPosition := rand.Intn(len(net.InputNodes))
+	inputPosition := net.random().Intn(len(net.InputNodes))
 	inputNodeIndex := net.InputNodes[inputPosition]
 	return inputNodeIndex
 }
@@ -301,7 +422,7 @@ func Combine(a, b []NeuronIndex) []NeuronIndex {
 func (net *Network) getAllConnectedNodes(nodeIndex NeuronIndex, distanceFromFirstNode int, alreadyHaveThese []NeuronIndex) []NeuronIndex {
 	allNodes := make([]NeuronIndex, 0, len(net.AllNodes))
 	node := net.AllNodes[nodeIndex]
-	if nodeIndex != net.OutputNode {
+	if !net.IsOutput(nodeIndex) {
 		node.distanceFromOutputNode = distanceFromFirstNode
 		net.AllNodes[nodeIndex] = node
 	}
@@ -323,18 +444,22 @@ func (net *Network) getAllConnectedNodes(nodeIndex NeuronIndex, distanceFromFirs
 	return allNodes
 }
 
-// ForEachConnected will only go through nodes that are connected to the output node (directly or indirectly)
+// ForEachConnected will only go through nodes that are connected to an output node (directly or indirectly)
 // Unconnected input nodes are not covered.
 func (net *Network) ForEachConnected(f func(n *Neuron)) {
-	// Start at the output node, traverse left towards the input nodes
+	// Start at the output nodes, traverse left towards the input nodes
 	// The network has a counter for how many nodes has been added/removed, for quick memory allocation here
 	// the final slice is to avoid circular connections
-	for _, nodeIndex := range net.getAllConnectedNodes(net.OutputNode, 0, []NeuronIndex{}) {
+	connected := []NeuronIndex{}
+	for _, outputNodeIndex := range net.Outputs() {
+		connected = Combine(connected, net.getAllConnectedNodes(outputNodeIndex, 0, connected))
+	}
+	for _, nodeIndex := range connected {
 		f(&(net.AllNodes[nodeIndex]))
 	}
 }
 
-// Connected returns a slice of neuron indexes, that are all connected to the output node (directly or indirectly)
+// Connected returns a slice of neuron indexes, that are all connected to an output node (directly or indirectly)
 func (net *Network) Connected() []NeuronIndex {
 	allConnected := make([]NeuronIndex, 0, len(net.AllNodes)) // Use a bit more memory, but don't allocate at every iteration
 	net.ForEachConnectedNodeIndex(func(ni NeuronIndex) {
@@ -372,17 +497,18 @@ func (net *Network) InsertRandomNode() bool {
 
 	// Find a random node among the nodes that are connected to the output node (directly or indirectly)
 	connectedNodes := net.Connected()
-	randomNodeIndexThatIsConnected := connectedNodes[rand.Intn(len(connectedNodes))]
+	randomNodeIndexThatIsConnected := connectedNodes[net.random().Intn(len(connectedNodes))]
 
-	// If this is one of the network input nodes, return
-	if net.IsInput(randomNodeIndexThatIsConnected) {
+	// If this is one of the network input nodes or the bias node, return
+	if net.IsInput(randomNodeIndexThatIsConnected) || net.IsBias(randomNodeIndexThatIsConnected) {
 		// Nothing to do here, the input nodes get their input from the input numbers
 		return false
 	}
 
-	// If this is the output node, and there are no inputs to the output node, return
-	if randomNodeIndexThatIsConnected == net.OutputNode && len(net.AllNodes[net.OutputNode].InputNodes) == 0 {
-		// Nothing to do here, no connections to the output node
+	// If there are no inputs to this node, return. This can be an output node, or a node that has had its
+	// input connections removed.
+	if len(net.AllNodes[randomNodeIndexThatIsConnected].InputNodes) == 0 {
+		// Nothing to do here, no connections to this node
 		return false
 	}
 
@@ -390,14 +516,14 @@ func (net *Network) InsertRandomNode() bool {
 	rightIndex := randomNodeIndexThatIsConnected
 	inputNodes := net.AllNodes[rightIndex].InputNodes
 
-	leftIndex := inputNodes[rand.Intn(len(inputNodes))]
+	leftIndex := inputNodes[net.random().Intn(len(inputNodes))]
 
 	// We now have a left and right node index, that we know are connected, replace this connection with
 	// one that goes through an entirely new node.
 
 	// Create a new node and connect it with the left node
-	newNode, newNodeIndex := net.NewNeuron()
-	err := newNode.AddInput(leftIndex)
+	_, newNodeIndex := net.newNeuron(net.newInnovation(leftIndex, rightIndex))
+	err := net.AllNodes[newNodeIndex].AddInput(leftIndex)
 	if err != nil {
 		panic(err)
 	}
@@ -445,11 +571,22 @@ func (net Network) Copy() *Network {
 	newNet.AllNodes = make([]Neuron, len(net.AllNodes))
 	for nodeIndex := range net.AllNodes {
 		// This copies the node and also sets the .Net pointer correctly to this network
-		newNet.AllNodes[nodeIndex] = newNet.AllNodes[nodeIndex].Copy(&newNet)
+		newNet.AllNodes[nodeIndex] = net.AllNodes[nodeIndex].Copy(&newNet)
 	}
-	newNet.InputNodes = net.InputNodes
+	newNet.InputNodes = append([]NeuronIndex{}, net.InputNodes...)
 	newNet.OutputNode = net.OutputNode
+	newNet.OutputNodes = append([]NeuronIndex{}, net.Outputs()...)
 	newNet.Weight = net.Weight
+	newNet.Recurrent = net.Recurrent
+	newNet.Bias = net.Bias
+	newNet.BiasNode = net.BiasNode
+	if net.state != nil {
+		newNet.state = append([]float64{}, net.state...)
+	}
+	newNet.rng = net.rng
+	newNet.innovations = net.innovations
+	newNet.complexity = net.complexity
+	newNet.allowed = net.allowed
 
 	// NOTE: It's important that a pointer to a Network is returned,
 	//       instead of an entire Network struct, so that the .Net pointers in the nodes point correctly.
@@ -462,8 +599,16 @@ func (net Network) Copy() *Network {
 // even if they are connected via another node.
 func (net Network) String() string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Network (%d nodes, %d input nodes, %d output node)\n", len(net.AllNodes), len(net.InputNodes), 1))
-	sb.WriteString("\tConnected inputs to output node: " + strconv.Itoa(len(net.AllNodes[net.OutputNode].InputNodes)) + "\n")
+	outputs := net.Outputs()
+	if len(outputs) == 1 {
+		sb.WriteString(fmt.Sprintf("Network (%d nodes, %d input nodes, %d output node)\n", len(net.AllNodes), len(net.InputNodes), 1))
+		sb.WriteString("\tConnected inputs to output node: " + strconv.Itoa(len(net.AllNodes[net.OutputNode].InputNodes)) + "\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Network (%d nodes, %d input nodes, %d output nodes)\n", len(net.AllNodes), len(net.InputNodes), len(outputs)))
+		for i, outputNodeIndex := range outputs {
+			sb.WriteString(fmt.Sprintf("\tConnected inputs to output node %d: %d\n", i, len(net.AllNodes[outputNodeIndex].InputNodes)))
+		}
+	}
 	for _, node := range net.AllNodes {
 		sb.WriteString("\t" + node.String() + "\n")
 	}
@@ -154,9 +154,7 @@ func TestComplexity(t *testing.T) {
 		inputs:                 5,
 		InitialConnectionRatio: 0.0,
 	})
-	// The complexity will vary, because the performance varies when
-	// estimating the complexity of each function.
-	// But the complexity compared between networks should still hold.
+	// The complexity compared between networks should hold, regardless of the complexity of each function
 	firstComplexity := net.Complexity()
 	// Adding a connection increases the complexity
 	net.AddConnection(0, 1)
@@ -379,3 +377,39 @@ func TestForEachConnectedNodeIndex(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestMultipleOutputs(t *testing.T) {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 5,
+		Outputs:                3,
+		InitialConnectionRatio: 1.0,
+		sharedWeight:           0.5,
+	})
+	if len(net.OutputNodes) != 3 || net.OutputNode != net.OutputNodes[0] {
+		t.Fatalf("expected 3 output nodes, got %v", net.OutputNodes)
+	}
+	for _, outputNodeIndex := range net.OutputNodes {
+		if !net.IsOutput(outputNodeIndex) || net.IsInput(outputNodeIndex) {
+			t.Fail()
+		}
+		if len(net.AllNodes[outputNodeIndex].InputNodes) != 5 {
+			t.Fail()
+		}
+	}
+	// Connections from an output node should be refused
+	if err := net.AddConnection(net.OutputNodes[1], net.OutputNodes[2]); err == nil {
+		t.Fail()
+	}
+	results := net.EvaluateAll([]float64{0.1, 0.2, 0.3, 0.4, 0.5})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0] != net.Evaluate([]float64{0.1, 0.2, 0.3, 0.4, 0.5}) {
+		t.Fail()
+	}
+	// Copies should keep the output nodes
+	if len(net.Copy().OutputNodes) != 3 {
+		t.Fail()
+	}
+}
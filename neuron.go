@@ -3,7 +3,6 @@ package wann
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 
 	"github.com/dave/jennifer/jen"
 )
@@ -14,6 +13,7 @@ type Neuron struct {
 	InputNodes             []NeuronIndex // pointers to other neurons
 	ActivationFunction     ActivationFunctionIndex
 	Value                  *float64
+	Innovation             int // Identifies the node across networks, for aligning networks when crossing them over
 	distanceFromOutputNode int // Used when traversing nodes and drawing diagrams
 	neuronIndex            NeuronIndex
 }
@@ -23,13 +23,19 @@ func (net *Network) NewBlankNeuron() (*Neuron, NeuronIndex) {
 	// Pre-allocate room for 16 connections and use Linear as the default activation function
 	neuron := Neuron{Net: net, InputNodes: make([]NeuronIndex, 0, 16), ActivationFunction: Swish}
 	neuron.neuronIndex = NeuronIndex(len(net.AllNodes))
+	neuron.Innovation = net.freshInnovation()
 	net.AllNodes = append(net.AllNodes, neuron)
 	return &neuron, neuron.neuronIndex
 }
 
-// NewNeuron creates a new *Neuron, with a randomly chosen activation function
+// NewNeuron creates a new *Neuron, with a randomly chosen activation function and a new innovation number
 func (net *Network) NewNeuron() (*Neuron, NeuronIndex) {
-	chosenActivationFunctionIndex := ActivationFunctionIndex(rand.Intn(len(ActivationFunctions)))
+	return net.newNeuron(net.freshInnovation())
+}
+
+// newNeuron creates a new *Neuron, with a randomly chosen activation function and the given innovation number
+func (net *Network) newNeuron(innovation int) (*Neuron, NeuronIndex) {
+	chosenActivationFunctionIndex := net.randomActivationFunction()
 	inputNodes := make([]NeuronIndex, 0, 16)
 	neuron := Neuron{
 		Net:                net,
@@ -40,6 +46,7 @@ func (net *Network) NewNeuron() (*Neuron, NeuronIndex) {
 	neuronIndex := NeuronIndex(len(net.AllNodes))
 	// Assign the neuron index in the net to the neuron
 	neuron.neuronIndex = neuronIndex
+	neuron.Innovation = innovation
 	// Add this neuron to the net
 	net.AllNodes = append(net.AllNodes, neuron)
 	return &neuron, neuronIndex
@@ -77,9 +84,14 @@ func (neuron *Neuron) Connect(net *Network) {
 	net.AllNodes[neuronIndex].neuronIndex = NeuronIndex(neuronIndex)
 }
 
-// RandomizeActivationFunction will choose a random activation function for this neuron
+// RandomizeActivationFunction will choose a random activation function for this neuron,
+// among the activation functions that are allowed for the network it belongs to
 func (neuron *Neuron) RandomizeActivationFunction() {
-	chosenActivationFunctionIndex := ActivationFunctionIndex(rand.Intn(len(ActivationFunctions)))
+	if neuron.Net != nil {
+		neuron.ActivationFunction = neuron.Net.randomActivationFunction()
+		return
+	}
+	chosenActivationFunctionIndex := ActivationFunctionIndex(globalRand.Intn(activationFunctionCount()))
 	neuron.ActivationFunction = chosenActivationFunctionIndex
 }
 
@@ -173,56 +185,10 @@ func (neuron *Neuron) InputNeuronsAreGood() bool {
 	return true
 }
 
-// evaluate will return a weighted sum of the input nodes,
-// using the .Value field if it is set and no input nodes are available.
-// returns true if the maximum number of evaluation loops is reached
-func (neuron *Neuron) evaluate(weight float64, maxEvaluationLoops *int) (float64, bool) {
-	if *maxEvaluationLoops <= 0 {
-		return 0.0, true
-	}
-	// Assume this is the Output neuron, recursively evaluating the result
-	// For each input neuron, evaluate them
-	summed := 0.0
-	counter := 0
-
-	for _, inputNeuronIndex := range neuron.InputNodes {
-		// Let each input neuron do its own evauluation, using the given weight
-		(*maxEvaluationLoops)--
-		// TODO: Figure out exactly why this one kicks in (and if it matters)
-		//       It only seems to kick in during "go test" and not in evolve/main.go
-		if int(inputNeuronIndex) >= len(neuron.Net.AllNodes) {
-			continue
-			//panic("TOO HIGH INPUT NEURON INDEX")
-		}
-		result, stopNow := neuron.Net.AllNodes[inputNeuronIndex].evaluate(weight, maxEvaluationLoops)
-		summed += result * weight
-		counter++
-		if stopNow || (*maxEvaluationLoops < 0) {
-			break
-		}
-	}
-	// No input neurons. Use the .Value field if it's not nil and this is not the output node
-	if counter == 0 && neuron.Value != nil && !neuron.IsOutput() {
-		return *(neuron.Value), false
-	}
-	// Return the averaged sum, or 0
-	if counter == 0 {
-		// This should never happen
-		return 0.0, false
-	}
-	// This should run, also when this neuron is the output neuron
-	f := neuron.GetActivationFunction()
-	//fmt.Println(neuron.ActivationFunction.Name() + " = " + neuron.ActivationFunction.String())
-	// Run the input through the activation function
-	// TODO: Does "retval := f(summed)"" perform better?, or the one that averages the sum first?
-	//retval := f(summed / float64(counter))
-	retval := f(summed)
-	return retval, false
-}
-
 // GetActivationFunction returns the activation function for this neuron
 func (neuron *Neuron) GetActivationFunction() func(float64) float64 {
-	return ActivationFunctions[neuron.ActivationFunction]
+	f, _ := activationFunction(neuron.ActivationFunction)
+	return f
 }
 
 // In checks if this neuron is in the given collection
@@ -250,16 +216,20 @@ func (neuron *Neuron) IsOutput() bool {
 	if neuron.Net == nil {
 		return false
 	}
-	return neuron.Net.OutputNode == neuron.neuronIndex
+	return neuron.Net.IsOutput(neuron.neuronIndex)
 }
 
 // Copy a Neuron to a new Neuron, and assign the pointer to the given network to .Net
 func (neuron Neuron) Copy(net *Network) Neuron {
 	var newNeuron Neuron
 	newNeuron.Net = net
-	newNeuron.InputNodes = neuron.InputNodes
+	newNeuron.InputNodes = append(make([]NeuronIndex, 0, cap(neuron.InputNodes)), neuron.InputNodes...)
 	newNeuron.ActivationFunction = neuron.ActivationFunction
-	newNeuron.Value = neuron.Value
+	newNeuron.Innovation = neuron.Innovation
+	if neuron.Value != nil {
+		v := *neuron.Value
+		newNeuron.Value = &v
+	}
 	newNeuron.distanceFromOutputNode = neuron.distanceFromOutputNode
 	newNeuron.neuronIndex = neuron.neuronIndex
 	return newNeuron
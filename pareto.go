@@ -0,0 +1,130 @@
+package wann
+
+import (
+	"math"
+	"sort"
+)
+
+// dominates checks if the objectives a are at least as good as b for every objective, and better for at least one.
+// Higher is better, for all objectives.
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// nondominatedFronts sorts the given objectives into Pareto fronts, as in NSGA-II.
+// The first front contains the indices of all the objectives that are not dominated by any other,
+// the second front contains the ones that are only dominated by the first front, and so on.
+func nondominatedFronts(objectives [][]float64) [][]int {
+	n := len(objectives)
+	dominatedBy := make([]int, n)  // How many others dominate each one
+	dominating := make([][]int, n) // The ones that each one dominates
+	var front []int
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			if dominates(objectives[i], objectives[j]) {
+				dominating[i] = append(dominating[i], j)
+			} else if dominates(objectives[j], objectives[i]) {
+				dominatedBy[i]++
+			}
+		}
+		if dominatedBy[i] == 0 {
+			front = append(front, i)
+		}
+	}
+	var fronts [][]int
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var next []int
+		for _, i := range front {
+			for _, j := range dominating[i] {
+				dominatedBy[j]--
+				if dominatedBy[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		front = next
+	}
+	return fronts
+}
+
+// crowdingDistances returns the crowding distance for each index in the given front, as in NSGA-II.
+// The distance is the sum of the normalized distances between the closest neighbours, for each objective.
+// The ones at the edges of the front get an infinite distance, so that they are always preferred.
+func crowdingDistances(objectives [][]float64, front []int) []float64 {
+	distances := make([]float64, len(front))
+	if len(front) == 0 {
+		return distances
+	}
+	order := make([]int, len(front))
+	for m := range objectives[front[0]] {
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return objectives[front[order[a]]][m] < objectives[front[order[b]]][m]
+		})
+		lowest := objectives[front[order[0]]][m]
+		highest := objectives[front[order[len(order)-1]]][m]
+		distances[order[0]] = math.Inf(1)
+		distances[order[len(order)-1]] = math.Inf(1)
+		if highest == lowest {
+			continue
+		}
+		for k := 1; k < len(order)-1; k++ {
+			distances[order[k]] += (objectives[front[order[k+1]]][m] - objectives[front[order[k-1]]][m]) / (highest - lowest)
+		}
+	}
+	return distances
+}
+
+// paretoRanking sorts the indices of the given objectives by Pareto front, and then by descending
+// crowding distance within each front. It also returns the Pareto fronts.
+func paretoRanking(objectives [][]float64) ([]int, [][]int) {
+	fronts := nondominatedFronts(objectives)
+	ranking := make([]int, 0, len(objectives))
+	for _, front := range fronts {
+		distances := crowdingDistances(objectives, front)
+		order := make([]int, len(front))
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return distances[order[a]] > distances[order[b]]
+		})
+		for _, k := range order {
+			ranking = append(ranking, front[k])
+		}
+	}
+	return ranking, fronts
+}
+
+// objectives returns the objectives that are used when config.MultiObjective is set, for one network:
+// the aggregated score, the best score for any of the shared weights and the negated number of connections.
+func (config *Config) objectives(net *Network, weightScores []float64) []float64 {
+	return []float64{
+		config.WeightAggregation.Aggregate(weightScores),
+		AggregateMax.Aggregate(weightScores),
+		1.0 - ConnectionComplexity(net),
+	}
+}
+
+// ParetoFront returns the networks in the first Pareto front of the last generation, from the last evolution
+// where config.MultiObjective was set. None of these networks are better than any of the others for all of
+// the objectives, so they represent different trade-offs between the score and the size of the network.
+// Each network uses the shared weight that it got the best score for.
+func (config *Config) ParetoFront() []*Network {
+	return config.paretoFront
+}
@@ -0,0 +1,71 @@
+package wann
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParetoRanking(t *testing.T) {
+	objectives := [][]float64{
+		{1.0, 1.0}, // 0: dominated by 2
+		{3.0, 0.0}, // 1: first front
+		{2.0, 2.0}, // 2: first front
+		{0.0, 3.0}, // 3: first front
+		{0.5, 0.5}, // 4: dominated by 0
+	}
+	ranking, fronts := paretoRanking(objectives)
+	expected := [][]int{{1, 2, 3}, {0}, {4}}
+	if len(fronts) != len(expected) {
+		t.Fatalf("expected fronts %v, got %v", expected, fronts)
+	}
+	for i := range expected {
+		if len(fronts[i]) != len(expected[i]) {
+			t.Fatalf("expected fronts %v, got %v", expected, fronts)
+		}
+		for k := range expected[i] {
+			if fronts[i][k] != expected[i][k] {
+				t.Fatalf("expected fronts %v, got %v", expected, fronts)
+			}
+		}
+	}
+	// The networks at the edges of the first front are ranked before the one in the middle
+	if ranking[2] != 2 || ranking[3] != 0 || ranking[4] != 4 {
+		t.Errorf("unexpected ranking %v", ranking)
+	}
+	distances := crowdingDistances(objectives, fronts[0])
+	if !math.IsInf(distances[0], 1) || !math.IsInf(distances[2], 1) || distances[1] != 2.0 {
+		t.Errorf("unexpected crowding distances %v", distances)
+	}
+}
+
+func TestEvolveMultiObjective(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         40,
+		RandomSeed:             commonSeed,
+		WeightSamples:          DefaultWeightSamples,
+		MultiObjective:         true,
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	front := config.ParetoFront()
+	if len(front) == 0 {
+		t.Fatal("expected a Pareto front")
+	}
+	for _, net := range front {
+		if c := net.Complexity(); c != 1.0 {
+			t.Errorf("expected no complexity penalty, got %f", c)
+		}
+	}
+	config.CompatibilityThreshold = 0.3
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err == nil {
+		t.Error("expected an error when combining speciation and multi-objective optimisation")
+	}
+}
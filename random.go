@@ -0,0 +1,37 @@
+package wann
+
+import (
+	"math/rand"
+)
+
+// globalSource is a rand.Source that draws from the global pseudo-random number generator in math/rand
+type globalSource struct{}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer, using rand.Int63
+func (globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+// Seed seeds the global pseudo-random number generator, using rand.Seed
+func (globalSource) Seed(seed int64) {
+	rand.Seed(seed)
+}
+
+// globalRand is used by networks that have not been given their own pseudo-random number generator
+var globalRand = rand.New(globalSource{})
+
+// SetRand sets the pseudo-random number generator that is used when this network is mutated.
+// The global pseudo-random number generator in math/rand is used if r is nil.
+// A *rand.Rand is not safe for concurrent use, so networks that are mutated concurrently
+// should not share the same one.
+func (net *Network) SetRand(r *rand.Rand) {
+	net.rng = r
+}
+
+// random returns the pseudo-random number generator for this network
+func (net *Network) random() *rand.Rand {
+	if net.rng == nil {
+		return globalRand
+	}
+	return net.rng
+}
@@ -0,0 +1,120 @@
+package wann
+
+import (
+	"errors"
+)
+
+// The state of each node while evaluating a time step, see Step
+const (
+	nodeUnvisited = iota
+	nodeVisiting
+	nodeVisited
+	nodeEmpty // Visited, but without a result, see Network.evaluateNode
+)
+
+// addRecurrentConnection adds a as an input to b, without swapping the order, even if this forms a cycle
+func (net *Network) addRecurrentConnection(a, b NeuronIndex) error {
+	if a == b {
+		return errors.New("can't connect to self")
+	}
+	if net.IsInput(b) || net.AllNodes[b].Value != nil {
+		return errors.New("error: b is an input node")
+	}
+	if net.AllNodes[b].HasInput(a) {
+		return errors.New("error: input already exists")
+	}
+	return net.AllNodes[b].AddInput(a)
+}
+
+// hasCycle checks if any node depends on itself, by following the input nodes
+func (net *Network) hasCycle() bool {
+	states := make([]int, len(net.AllNodes))
+	var visit func(ni NeuronIndex) bool
+	visit = func(ni NeuronIndex) bool {
+		switch states[ni] {
+		case nodeVisiting:
+			return true
+		case nodeVisited:
+			return false
+		}
+		states[ni] = nodeVisiting
+		for _, inputNodeIndex := range net.AllNodes[ni].InputNodes {
+			if int(inputNodeIndex) < len(net.AllNodes) && visit(inputNodeIndex) {
+				return true
+			}
+		}
+		states[ni] = nodeVisited
+		return false
+	}
+	for i := range net.AllNodes {
+		if visit(NeuronIndex(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset sets the hidden state of the network to 0, for starting on a new sequence of time steps
+func (net *Network) Reset() {
+	net.state = make([]float64, len(net.AllNodes))
+}
+
+// Step evaluates the network for one time step, and returns one result per output node.
+// The nodes are visited depth first from the output nodes, in the same order as EvaluateAll, and each node is
+// evaluated once per time step. A connection from a node that is still being evaluated, which is a connection
+// that closes a cycle, uses the value that node had after the previous time step, or 0 after Reset.
+// All other connections use the value from this time step. The input values are assigned as for Evaluate.
+// The hidden state is reset if nodes have been added or removed since the previous time step.
+func (net *Network) Step(inputValues []float64) []float64 {
+	if len(net.state) != len(net.AllNodes) {
+		net.Reset()
+	}
+	net.setInputValues(inputValues)
+	values := make([]float64, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	outputs := net.Outputs()
+	results := make([]float64, len(outputs))
+	for i, outputNodeIndex := range outputs {
+		results[i] = net.stepNode(outputNodeIndex, values, states)
+	}
+	// Keep the values of the nodes that were evaluated, for the next time step
+	for i, state := range states {
+		if state == nodeVisited {
+			net.state[i] = values[i]
+		}
+	}
+	return results
+}
+
+// stepNode evaluates the given node for the current time step, in the same way as evaluateNode,
+// but using the previous value for connections that close a cycle
+func (net *Network) stepNode(nodeIndex NeuronIndex, values []float64, states []int) float64 {
+	if states[nodeIndex] == nodeVisited {
+		return values[nodeIndex]
+	}
+	states[nodeIndex] = nodeVisiting
+	neuron := &net.AllNodes[nodeIndex]
+	summed := 0.0
+	counter := 0
+	for _, inputNeuronIndex := range neuron.InputNodes {
+		if int(inputNeuronIndex) >= len(net.AllNodes) {
+			continue
+		}
+		if states[inputNeuronIndex] == nodeVisiting {
+			summed += net.state[inputNeuronIndex] * net.Weight
+		} else {
+			summed += net.stepNode(inputNeuronIndex, values, states) * net.Weight
+		}
+		counter++
+	}
+	switch {
+	case counter == 0 && neuron.Value != nil && !neuron.IsOutput():
+		values[nodeIndex] = *(neuron.Value)
+	case counter == 0:
+		values[nodeIndex] = 0.0
+	default:
+		values[nodeIndex] = neuron.GetActivationFunction()(summed)
+	}
+	states[nodeIndex] = nodeVisited
+	return values[nodeIndex]
+}
@@ -0,0 +1,112 @@
+package wann
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStep(t *testing.T) {
+	config := &Config{
+		inputs:                 1,
+		InitialConnectionRatio: 1.0,
+		Recurrent:              true,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	_, h := net.NewNeuron()
+	for i := range net.AllNodes {
+		net.AllNodes[i].ActivationFunction = Linear
+	}
+	if err := net.AddConnection(h, net.OutputNode); err != nil {
+		t.Fatal(err)
+	}
+	// The output node is connected back to the hidden node, which forms a cycle
+	if err := net.AddConnection(net.OutputNode, h); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AddConnection(h, h); err == nil {
+		t.Error("expected an error when connecting a node to itself")
+	}
+	net.SetWeight(0.5)
+	// output = w*input + w*hidden, where hidden = w*output from the previous time step
+	expected := []float64{0.5, 0.625, 0.65625}
+	for i, e := range expected {
+		if result := net.Step([]float64{1.0})[0]; result != e {
+			t.Errorf("time step %d: expected %v, got %v", i, e, result)
+		}
+	}
+	net.Reset()
+	if result := net.Step([]float64{1.0})[0]; result != expected[0] {
+		t.Errorf("expected %v after Reset, got %v", expected[0], result)
+	}
+	// The hidden state is copied together with the network
+	c := net.Copy()
+	if a, b := net.Step([]float64{1.0})[0], c.Step([]float64{1.0})[0]; a != b || a != expected[1] {
+		t.Errorf("expected %v for both networks, got %v and %v", expected[1], a, b)
+	}
+
+	data, err := json.Marshal(net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Network
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Recurrent {
+		t.Error("expected the loaded network to be recurrent")
+	}
+	if result := loaded.Step([]float64{1.0})[0]; result != expected[0] {
+		t.Errorf("expected %v, got %v", expected[0], result)
+	}
+}
+
+func TestAddConnectionNotRecurrent(t *testing.T) {
+	config := &Config{
+		inputs:                 1,
+		InitialConnectionRatio: 1.0,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	_, h := net.NewNeuron()
+	// The order is swapped, so that the output node is not connected to the hidden node
+	if err := net.AddConnection(net.OutputNode, h); err != nil {
+		t.Fatal(err)
+	}
+	if net.AllNodes[h].HasInput(net.OutputNode) || !net.AllNodes[net.OutputNode].HasInput(h) {
+		t.Error("expected a connection from the hidden node to the output node")
+	}
+}
+
+func TestEvolveRecurrent(t *testing.T) {
+	// Predict the next value in a repeating sequence, given the current value
+	sequence := []float64{0.0, 1.0, 1.0, 0.0, 1.0, 1.0, 0.0, 1.0, 1.0}
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            20,
+		PopulationSize:         30,
+		Recurrent:              true,
+		CrossoverRate:          0.25,
+		MutationRates:          MutationRates{InsertNode: 1.0, AddConnection: 2.0, ChangeActivationFunction: 1.0, RewireConnection: 1.0},
+		RandomSeed:             commonSeed,
+		Fitness: func(net *Network) float64 {
+			net.Reset()
+			score := 0.0
+			for i := 0; i+1 < len(sequence); i++ {
+				if (net.Step([]float64{sequence[i]})[0] > 0.5) == (sequence[i+1] > 0.5) {
+					score++
+				}
+			}
+			return score
+		},
+	}
+	net, err := config.Evolve([][]float64{{0.0}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !net.Recurrent {
+		t.Error("expected the evolved network to be recurrent")
+	}
+}
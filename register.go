@@ -0,0 +1,107 @@
+package wann
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// CustomActivationFunction is an activation function that can be registered at runtime, with RegisterActivationFunction
+type CustomActivationFunction struct {
+	// Name is used when saving networks as JSON and when drawing diagrams. It must be unique.
+	Name string
+	// Func is used when evaluating networks
+	Func func(float64) float64
+	// Expression is a Go expression for the function, where each "%s" is replaced with the input expression.
+	// For example "math.Max(-1.0, math.Min(1.0, %s))". Statement is used for creating it, if this is empty.
+	// No packages are imported when a statement is created from the expression, so Statement should also
+	// be set if the expression uses packages like math.
+	Expression string
+	// Statement builds the function as a jennifer statement, given the inner statement.
+	// Expression is used for creating it, if this is nil.
+	Statement func(inner *jen.Statement) *jen.Statement
+	// Complexity is how complex the function is, where 1.0 is as complex as the most complex built-in function
+	Complexity float64
+}
+
+// customActivationFunctions are the registered activation functions
+var customActivationFunctions = make(map[ActivationFunctionIndex]CustomActivationFunction)
+
+// activationFunctionsMut guards ActivationFunctions, ComplexityEstimate and customActivationFunctions,
+// so that activation functions can be registered while networks are being evolved or evaluated
+var activationFunctionsMut sync.RWMutex
+
+// RegisterActivationFunction adds an activation function to ActivationFunctions and ComplexityEstimate,
+// so that it can be chosen when networks are modified, and returns the index of the new function.
+// This package reads the maps while holding a lock, so functions can be registered at any time,
+// but code that reads ActivationFunctions or ComplexityEstimate directly must not run at the same time.
+func RegisterActivationFunction(caf CustomActivationFunction) (ActivationFunctionIndex, error) {
+	if caf.Name == "" {
+		return Linear, errors.New("the activation function has no name")
+	}
+	if caf.Func == nil {
+		return Linear, errors.New("the activation function " + caf.Name + " has no Func")
+	}
+	if caf.Expression == "" && caf.Statement == nil {
+		return Linear, errors.New("the activation function " + caf.Name + " needs either an Expression or a Statement")
+	}
+	activationFunctionsMut.Lock()
+	defer activationFunctionsMut.Unlock()
+	if _, err := activationFunctionByName(caf.Name); err == nil {
+		return Linear, errors.New("there is already an activation function named " + caf.Name)
+	}
+	// The indices must be contiguous, since activation functions are chosen with rand.Intn(len(ActivationFunctions))
+	afi := ActivationFunctionIndex(len(ActivationFunctions))
+	ActivationFunctions[afi] = caf.Func
+	ComplexityEstimate[afi] = caf.Complexity
+	customActivationFunctions[afi] = caf
+	return afi, nil
+}
+
+// activationFunction returns the function for the given activation function index
+func activationFunction(afi ActivationFunctionIndex) (func(float64) float64, bool) {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	f, ok := ActivationFunctions[afi]
+	return f, ok
+}
+
+// activationFunctionCount returns how many activation functions there are
+func activationFunctionCount() int {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	return len(ActivationFunctions)
+}
+
+// complexityEstimate returns the estimated complexity of the given activation function
+func complexityEstimate(afi ActivationFunctionIndex) float64 {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	return ComplexityEstimate[afi]
+}
+
+// customActivationFunction returns the registered activation function with the given index, if there is one
+func customActivationFunction(afi ActivationFunctionIndex) (CustomActivationFunction, bool) {
+	activationFunctionsMut.RLock()
+	defer activationFunctionsMut.RUnlock()
+	caf, ok := customActivationFunctions[afi]
+	return caf, ok
+}
+
+// customExpression returns the Go expression for a registered activation function
+func (caf CustomActivationFunction) customExpression(varName string) string {
+	if caf.Expression != "" {
+		return strings.ReplaceAll(caf.Expression, "%s", varName)
+	}
+	return caf.Statement(jen.Id(varName)).GoString()
+}
+
+// customStatement returns the jennifer statement for a registered activation function
+func (caf CustomActivationFunction) customStatement(inner *jen.Statement) *jen.Statement {
+	if caf.Statement != nil {
+		return caf.Statement(inner)
+	}
+	return jen.Id(caf.customExpression("(" + inner.GoString() + ")"))
+}
@@ -0,0 +1,109 @@
+package wann
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRegisterActivationFunction(t *testing.T) {
+	clipped, err := RegisterActivationFunction(CustomActivationFunction{
+		Name:       "ClippedLinear",
+		Func:       func(x float64) float64 { return math.Max(-1.0, math.Min(1.0, x)) },
+		Expression: "func(c float64) float64 { if c > 1 { return 1 }; if c < -1 { return -1 }; return c }(%s)",
+		Complexity: 0.3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	triangle, err := RegisterActivationFunction(CustomActivationFunction{
+		Name: "Triangle",
+		Func: func(x float64) float64 { return 1.0 - 2.0*math.Abs(x-2.0*math.Floor(x/2.0)-1.0) },
+		Statement: func(inner *jen.Statement) *jen.Statement {
+			// 1.0 - 2.0*math.Abs(2.0*((inner)/2.0 - math.Floor((inner)/2.0)) - 1.0)
+			half := jen.Parens(inner).Op("/").Lit(2.0)
+			return jen.Lit(1.0).Op("-").Lit(2.0).Op("*").Qual("math", "Abs").Call(
+				jen.Lit(2.0).Op("*").Parens(half.Clone().Op("-").Qual("math", "Floor").Call(half.Clone())).Op("-").Lit(1.0))
+		},
+		Complexity: 0.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, afi := range []ActivationFunctionIndex{clipped, triangle} {
+			delete(ActivationFunctions, afi)
+			delete(ComplexityEstimate, afi)
+			delete(customActivationFunctions, afi)
+		}
+	})
+
+	if _, err := RegisterActivationFunction(CustomActivationFunction{Name: "Triangle", Func: math.Abs, Expression: "%s"}); err == nil {
+		t.Error("expected an error when registering the same name twice")
+	}
+	if afi, err := ActivationFunctionByName("ClippedLinear"); err != nil || afi != clipped {
+		t.Errorf("could not find the registered activation function by name: %v", err)
+	}
+	if clipped.Call(2.5) != 1.0 || triangle.Call(0.5) != 0.0 {
+		t.Errorf("unexpected results: %f and %f", clipped.Call(2.5), triangle.Call(0.5))
+	}
+	if expression := clipped.String(); expression != "func(c float64) float64 { if c > 1 { return 1 }; if c < -1 { return -1 }; return c }(x)" {
+		t.Errorf("unexpected expression: %s", expression)
+	}
+	for _, afi := range []ActivationFunctionIndex{clipped, triangle} {
+		for _, x := range []float64{-1.5, 0.25} {
+			result, err := afi.GoRun(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(result-afi.Call(x)) > 1e-9 {
+				t.Errorf("%s: expected the generated code to give %f, got %f", afi.Name(), afi.Call(x), result)
+			}
+		}
+	}
+}
+
+func TestRegisterActivationFunctionConcurrently(t *testing.T) {
+	var registered []ActivationFunctionIndex
+	done := make(chan error)
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			afi, err := RegisterActivationFunction(CustomActivationFunction{
+				Name:       fmt.Sprintf("Concurrent%d", i),
+				Func:       math.Cbrt,
+				Expression: "math.Cbrt(%s)",
+				Complexity: 0.4,
+			})
+			if err != nil {
+				done <- err
+				return
+			}
+			registered = append(registered, afi)
+		}
+	}()
+	t.Cleanup(func() {
+		activationFunctionsMut.Lock()
+		defer activationFunctionsMut.Unlock()
+		for _, afi := range registered {
+			delete(ActivationFunctions, afi)
+			delete(ComplexityEstimate, afi)
+			delete(customActivationFunctions, afi)
+		}
+	})
+	// Networks are modified and scored while the functions are registered
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            5,
+		PopulationSize:         20,
+		RandomSeed:             commonSeed,
+	}
+	if _, err := config.Evolve([][]float64{{0.0, 1.0}, {1.0, 0.0}}, []float64{1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
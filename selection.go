@@ -0,0 +1,132 @@
+package wann
+
+import (
+	"math/rand"
+)
+
+// Selector decides which networks in a population are kept and which ones get offspring, for each generation
+type Selector interface {
+	// Name returns a name for the selection strategy
+	Name() string
+	// Elites returns how many of the best networks are kept unchanged in the next generation
+	Elites(populationSize int) int
+	// Select returns the rank of a network to use as a parent, where 0 is the best network.
+	// The given scores are sorted in descending order.
+	Select(scores []float64, r *rand.Rand) int
+}
+
+// TruncationSelection only lets the best fraction of the population have offspring, with equal probability.
+// This is the default selection strategy, with a Fraction of 0.07.
+type TruncationSelection struct {
+	// The fraction of the population that may have offspring. At least the best network is always selected.
+	Fraction float64
+	// How many of the best networks are kept unchanged. All the selected networks are kept if this is 0.
+	Elitism int
+}
+
+// selected returns how many of the best networks may have offspring
+func (s *TruncationSelection) selected(populationSize int) int {
+	n := int(float64(populationSize) * s.Fraction)
+	if n < 1 {
+		// Always select at least the best network, also for small populations
+		n = 1
+	}
+	return n
+}
+
+// Name returns "truncation"
+func (s *TruncationSelection) Name() string {
+	return "truncation"
+}
+
+// Elites returns the Elitism count, or the number of selected networks if it is 0
+func (s *TruncationSelection) Elites(populationSize int) int {
+	if s.Elitism > 0 {
+		return s.Elitism
+	}
+	return s.selected(populationSize)
+}
+
+// Select returns the rank of a random network among the best fraction of the population
+func (s *TruncationSelection) Select(scores []float64, r *rand.Rand) int {
+	return r.Intn(s.selected(len(scores)))
+}
+
+// TournamentSelection picks a number of random networks, and lets the best one of them have offspring.
+// Larger tournaments give a higher selection pressure.
+type TournamentSelection struct {
+	// How many networks take part in each tournament. 2 is used if this is 0.
+	Size int
+	// How many of the best networks are kept unchanged. The best network is kept if this is 0.
+	Elitism int
+}
+
+// Name returns "tournament"
+func (s *TournamentSelection) Name() string {
+	return "tournament"
+}
+
+// Elites returns the Elitism count, or 1 if it is 0, so that the best network is never lost
+func (s *TournamentSelection) Elites(populationSize int) int {
+	if s.Elitism > 0 {
+		return s.Elitism
+	}
+	return 1
+}
+
+// Select returns the best rank out of a number of random ranks
+func (s *TournamentSelection) Select(scores []float64, r *rand.Rand) int {
+	size := s.Size
+	if size < 1 {
+		size = 2
+	}
+	best := r.Intn(len(scores))
+	for i := 1; i < size; i++ {
+		if rank := r.Intn(len(scores)); rank < best {
+			best = rank
+		}
+	}
+	return best
+}
+
+// RankSelection lets each network have offspring with a probability that is proportional to its rank,
+// so that the best of n networks is n times as likely to be selected as the worst one.
+// Unlike selecting in proportion to the scores, this does not depend on the scale of the scores.
+type RankSelection struct {
+	// How many of the best networks are kept unchanged. The best network is kept if this is 0.
+	Elitism int
+}
+
+// Name returns "rank"
+func (s *RankSelection) Name() string {
+	return "rank"
+}
+
+// Elites returns the Elitism count, or 1 if it is 0, so that the best network is never lost
+func (s *RankSelection) Elites(populationSize int) int {
+	if s.Elitism > 0 {
+		return s.Elitism
+	}
+	return 1
+}
+
+// Select returns a random rank, where the network at rank i of n has a weight of n - i
+func (s *RankSelection) Select(scores []float64, r *rand.Rand) int {
+	n := len(scores)
+	x := r.Intn(n * (n + 1) / 2)
+	for rank := 0; rank < n; rank++ {
+		x -= n - rank
+		if x < 0 {
+			return rank
+		}
+	}
+	return n - 1
+}
+
+// selector returns the configured selection strategy, or truncation selection of the best 7% if none is set
+func (config *Config) selector() Selector {
+	if config.Selector == nil {
+		return &TruncationSelection{Fraction: 0.07}
+	}
+	return config.Selector
+}
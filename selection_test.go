@@ -0,0 +1,75 @@
+package wann
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSelectors(t *testing.T) {
+	scores := make([]float64, 100)
+	for i := range scores {
+		scores[i] = float64(len(scores) - i)
+	}
+	r := rand.New(rand.NewSource(commonSeed))
+	for _, selector := range []Selector{
+		&TruncationSelection{Fraction: 0.1},
+		&TournamentSelection{Size: 3},
+		&RankSelection{},
+	} {
+		// The better half should be selected more often than the worse half
+		better := 0
+		for i := 0; i < 1000; i++ {
+			rank := selector.Select(scores, r)
+			if rank < 0 || rank >= len(scores) {
+				t.Fatalf("%s: selected rank %d is out of range", selector.Name(), rank)
+			}
+			if rank < len(scores)/2 {
+				better++
+			}
+		}
+		if better < 600 {
+			t.Errorf("%s: expected the better half to be selected more often, got %d of 1000", selector.Name(), better)
+		}
+	}
+	truncation := &TruncationSelection{Fraction: 0.1}
+	for i := 0; i < 100; i++ {
+		if rank := truncation.Select(scores, r); rank >= 10 {
+			t.Fatalf("expected only the best 10%% to be selected, got rank %d", rank)
+		}
+	}
+	if elites := truncation.Elites(len(scores)); elites != 10 {
+		t.Errorf("expected the selected networks to be kept, got %d", elites)
+	}
+	truncation.Elitism = 2
+	if elites := truncation.Elites(len(scores)); elites != 2 {
+		t.Errorf("expected 2 networks to be kept, got %d", elites)
+	}
+	for _, selector := range []Selector{&TournamentSelection{}, &RankSelection{}} {
+		if elites := selector.Elites(len(scores)); elites != 1 {
+			t.Errorf("%s: expected the best network to be kept by default, got %d", selector.Name(), elites)
+		}
+	}
+}
+
+func TestEvolveSelector(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	for _, selector := range []Selector{
+		&TournamentSelection{Size: 4, Elitism: 2},
+		&RankSelection{Elitism: 1},
+	} {
+		config := &Config{
+			InitialConnectionRatio: 0.5,
+			Generations:            10,
+			PopulationSize:         40,
+			RandomSeed:             commonSeed,
+			Selector:               selector,
+		}
+		if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+			t.Fatalf("%s: %v", selector.Name(), err)
+		}
+	}
+}
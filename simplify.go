@@ -0,0 +1,143 @@
+package wann
+
+// Simplify makes the network smaller, without changing the results of Evaluate and EvaluateAll.
+// Nodes that are not connected to an output node are removed, except for the input nodes and the bias node, and the
+// nodes after them get lower neuron indices, so that the indices stay contiguous. Two Inv nodes in a row, where the
+// first one is only connected to the second one, are changed to Linear nodes, since -(w * -x) is the same as w * x,
+// unless Linear is not among the allowed activation functions. The results are the same for any shared weight.
+// The hidden state of a recurrent network is reset. Returns the number of nodes that were removed.
+func (net *Network) Simplify() int {
+	net.foldDoubleInversions()
+	removed := net.removeUnconnectedNodes()
+	net.state = nil
+	return removed
+}
+
+// SimplifyForWeight is like Simplify, but also collapses chains of hidden Linear nodes with one input node each,
+// where the given shared weight cancels out, since every connection is multiplied with the shared weight:
+// single Linear nodes are removed if the weight is 1, and two Linear nodes in a row are removed if it is -1.
+// The results are only the same for the given shared weight, so this is for networks that are done training.
+// Chains are kept in recurrent networks. Returns the number of nodes that were removed.
+func (net *Network) SimplifyForWeight(weight float64) int {
+	net.foldDoubleInversions()
+	net.collapseIdentityChains(weight)
+	removed := net.removeUnconnectedNodes()
+	net.state = nil
+	return removed
+}
+
+// consumers counts how many nodes each node is an input node to
+func (net *Network) consumers() []int {
+	consumers := make([]int, len(net.AllNodes))
+	for _, node := range net.AllNodes {
+		for _, inputNodeIndex := range node.InputNodes {
+			if int(inputNodeIndex) < len(net.AllNodes) {
+				consumers[inputNodeIndex]++
+			}
+		}
+	}
+	return consumers
+}
+
+// isIdentity checks if the given node is a hidden Linear node with one input node
+func (net *Network) isIdentity(ni NeuronIndex) bool {
+	node := &net.AllNodes[ni]
+	if node.ActivationFunction != Linear || len(node.InputNodes) != 1 || int(node.InputNodes[0]) >= len(net.AllNodes) {
+		return false
+	}
+	return node.Value == nil && !net.IsInput(ni) && !net.IsOutput(ni) && !net.IsBias(ni)
+}
+
+// collapseIdentityChains connects the nodes after a chain of hidden Linear nodes directly to the node before the
+// chain, where the given shared weight cancels out. For a weight of 1, each Linear node is skipped, since 1 * (1 * x) is
+// the same as 1 * x. For a weight of -1, two Linear nodes in a row are skipped, where the first one is only
+// connected to the second one, since -1 * (-1 * (-1 * x)) is the same as -1 * x. The skipped nodes are left
+// unconnected. Nothing is done for recurrent networks.
+func (net *Network) collapseIdentityChains(weight float64) {
+	if net.Recurrent || (weight != 1.0 && weight != -1.0) {
+		return
+	}
+	for changed := true; changed; {
+		changed = false
+		consumers := net.consumers()
+		for i := range net.AllNodes {
+			ni := NeuronIndex(i)
+			if consumers[ni] == 0 || !net.isIdentity(ni) {
+				continue
+			}
+			replacement := net.AllNodes[ni].InputNodes[0]
+			if weight == -1.0 {
+				if consumers[replacement] != 1 || !net.isIdentity(replacement) {
+					continue
+				}
+				replacement = net.AllNodes[replacement].InputNodes[0]
+			}
+			// Skip the chain, unless a node after it is already connected to the node before it
+			skip := true
+			for j := range net.AllNodes {
+				node := &net.AllNodes[j]
+				if node.HasInput(ni) && (node.HasInput(replacement) || NeuronIndex(j) == replacement) {
+					skip = false
+					break
+				}
+			}
+			if !skip {
+				continue
+			}
+			for j := range net.AllNodes {
+				for k, inputNodeIndex := range net.AllNodes[j].InputNodes {
+					if inputNodeIndex == ni {
+						net.AllNodes[j].InputNodes[k] = replacement
+					}
+				}
+			}
+			changed = true
+			consumers = net.consumers()
+		}
+	}
+}
+
+// foldDoubleInversions changes pairs of Inv nodes a -> b to Linear nodes, if a is a hidden node that is
+// only connected to b, and b has no other input nodes. Nothing is done if Linear is not allowed for this network.
+func (net *Network) foldDoubleInversions() {
+	if !net.isAllowed(Linear) {
+		return
+	}
+	consumers := net.consumers()
+	for i := range net.AllNodes {
+		b := &net.AllNodes[i]
+		if b.ActivationFunction != Inv || len(b.InputNodes) != 1 {
+			continue
+		}
+		ai := b.InputNodes[0]
+		if int(ai) >= len(net.AllNodes) || ai == NeuronIndex(i) {
+			continue
+		}
+		a := &net.AllNodes[ai]
+		if a.ActivationFunction != Inv || consumers[ai] != 1 || a.Value != nil || net.IsInput(ai) || net.IsOutput(ai) || net.IsBias(ai) {
+			continue
+		}
+		a.ActivationFunction = Linear
+		b.ActivationFunction = Linear
+	}
+}
+
+// removeUnconnectedNodes removes the nodes that are not connected to an output node, directly or indirectly,
+// except for the input nodes and the bias node. Returns the number of nodes that were removed.
+func (net *Network) removeUnconnectedNodes() int {
+	connected := make([]bool, len(net.AllNodes))
+	for _, ni := range net.Connected() {
+		connected[ni] = true
+	}
+	removed := 0
+	// Remove the nodes from the end, so that the indices of the remaining nodes that are checked do not change
+	for i := len(net.AllNodes) - 1; i >= 0; i-- {
+		ni := NeuronIndex(i)
+		if connected[i] || net.IsInput(ni) || net.IsOutput(ni) || net.IsBias(ni) {
+			continue
+		}
+		net.removeNode(ni)
+		removed++
+	}
+	return removed
+}
@@ -0,0 +1,188 @@
+package wann
+
+import (
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	config := &Config{
+		inputs:                 3,
+		InitialConnectionRatio: 1.0,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	// An unconnected node
+	net.NewNeuron()
+	// Two Inv nodes in a row, between the first input node and the output node
+	_, a := net.NewNeuron()
+	_, b := net.NewNeuron()
+	net.AllNodes[a].ActivationFunction = Inv
+	net.AllNodes[b].ActivationFunction = Inv
+	if err := net.AllNodes[net.OutputNode].RemoveInput(net.InputNodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AllNodes[a].AddInput(net.InputNodes[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AllNodes[b].AddInput(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AllNodes[net.OutputNode].AddInput(b); err != nil {
+		t.Fatal(err)
+	}
+	// Another unconnected node, that has an input node
+	_, c := net.NewNeuron()
+	if err := net.AllNodes[c].AddInput(b); err != nil {
+		t.Fatal(err)
+	}
+
+	inputData := [][]float64{{0.0, 1.0, 0.0}, {1.0, 1.0, 1.0}, {-0.5, 0.25, 1.0}}
+	var expected [][]float64
+	for _, weight := range DefaultWeightSamples {
+		net.SetWeight(weight)
+		for _, inputValues := range inputData {
+			expected = append(expected, net.EvaluateAll(inputValues))
+		}
+	}
+	nodeCount := len(net.AllNodes)
+	if removed := net.Simplify(); removed != 2 || len(net.AllNodes) != nodeCount-2 {
+		t.Fatalf("expected 2 nodes to be removed, got %d", removed)
+	}
+	// The Inv nodes now have the indices of the removed node and the first Inv node
+	if net.AllNodes[a-1].ActivationFunction != Linear || net.AllNodes[b-1].ActivationFunction != Linear {
+		t.Error("expected the two Inv nodes to be changed to Linear nodes")
+	}
+	for i, node := range net.AllNodes {
+		if node.neuronIndex != NeuronIndex(i) {
+			t.Errorf("expected neuron index %d, got %d", i, node.neuronIndex)
+		}
+	}
+	i := 0
+	for _, weight := range DefaultWeightSamples {
+		net.SetWeight(weight)
+		for _, inputValues := range inputData {
+			results := net.EvaluateAll(inputValues)
+			for j := range results {
+				if results[j] != expected[i][j] {
+					t.Errorf("weight %f: expected %v, got %v", weight, expected[i], results)
+				}
+			}
+			i++
+		}
+	}
+}
+
+func TestSimplifyIdentityChains(t *testing.T) {
+	// A network where the first input node is connected to the output node through a chain of three Linear nodes
+	newNetwork := func(weight float64) *Network {
+		net := NewNetwork(&Config{inputs: 6})
+		net.UpdateNetworkPointers()
+		previous := net.InputNodes[0]
+		for i := 0; i < 3; i++ {
+			_, ni := net.NewNeuron()
+			net.AllNodes[ni].ActivationFunction = Linear
+			if err := net.AllNodes[ni].AddInput(previous); err != nil {
+				t.Fatal(err)
+			}
+			previous = ni
+		}
+		net.AllNodes[net.OutputNode].ActivationFunction = Linear
+		net.AllNodes[net.OutputNode].InputNodes = []NeuronIndex{previous, net.InputNodes[1]}
+		net.UpdateNetworkPointers()
+		net.SetWeight(weight)
+		return &net
+	}
+	inputValues := []float64{0.5, -0.25, 1.0, 0.0, 0.75, 1.0}
+	for _, tc := range []struct {
+		weight  float64
+		removed int
+	}{
+		{1.0, 3},  // Every Linear node is skipped
+		{-1.0, 2}, // Two Linear nodes in a row are skipped, and one is kept
+		{0.5, 0},  // The weight does not cancel out
+	} {
+		net := newNetwork(tc.weight)
+		expected := net.Evaluate(inputValues)
+		// Simplify keeps the chains, since the results must be the same for every shared weight
+		if removed := net.Simplify(); removed != 0 {
+			t.Errorf("weight %f: expected no nodes to be removed, got %d", tc.weight, removed)
+		}
+		if removed := net.SimplifyForWeight(tc.weight); removed != tc.removed {
+			t.Errorf("weight %f: expected %d nodes to be removed, got %d", tc.weight, tc.removed, removed)
+		}
+		if result := net.Evaluate(inputValues); result != expected {
+			t.Errorf("weight %f: expected %v, got %v", tc.weight, expected, result)
+		}
+		if result, err := net.Compile().Evaluate(inputValues); err != nil || result != expected {
+			t.Errorf("weight %f: expected %v from the compiled network, got %v", tc.weight, expected, result)
+		}
+	}
+}
+
+func TestSimplifyAllowedActivationFunctions(t *testing.T) {
+	// Two Inv nodes in a row, between the first input node and the output node
+	net := NewNetwork(&Config{inputs: 2})
+	net.UpdateNetworkPointers()
+	_, a := net.NewNeuron()
+	_, b := net.NewNeuron()
+	net.AllNodes[a].ActivationFunction = Inv
+	net.AllNodes[b].ActivationFunction = Inv
+	net.AllNodes[a].InputNodes = []NeuronIndex{net.InputNodes[0]}
+	net.AllNodes[b].InputNodes = []NeuronIndex{a}
+	net.AllNodes[net.OutputNode].InputNodes = []NeuronIndex{b}
+	net.UpdateNetworkPointers()
+	net.SetAllowedActivationFunctions(Inv, Sigmoid)
+	net.Simplify()
+	if net.AllNodes[a].ActivationFunction != Inv || net.AllNodes[b].ActivationFunction != Inv {
+		t.Error("expected the Inv nodes to be kept, since Linear is not allowed")
+	}
+	net.SetAllowedActivationFunctions(Inv, Linear)
+	net.Simplify()
+	if net.AllNodes[a].ActivationFunction != Linear || net.AllNodes[b].ActivationFunction != Linear {
+		t.Error("expected the two Inv nodes to be changed to Linear nodes")
+	}
+}
+
+func TestSimplifyModified(t *testing.T) {
+	config := &Config{
+		inputs:                 4,
+		Outputs:                2,
+		InitialConnectionRatio: 0.5,
+		Bias:                   true,
+		RandomSeed:             commonSeed,
+	}
+	config.initRandom()
+	inputData := [][]float64{{0.0, 1.0, 0.0, 1.0}, {1.0, 1.0, 1.0, 0.0}, {-0.5, 0.25, 1.0, 0.75}}
+	removed := 0
+	for n := 0; n < 20; n++ {
+		net := NewNetwork(config)
+		net.UpdateNetworkPointers()
+		for i := 0; i < n; i++ {
+			net.ModifyWith(MutationRates{InsertNode: 1.0, AddConnection: 1.0, ChangeActivationFunction: 1.0, RemoveConnection: 0.5}, 10)
+		}
+		before := net.Copy()
+		removed += net.Simplify()
+		for _, ni := range net.Unconnected() {
+			if !net.IsInput(ni) && !net.IsBias(ni) {
+				t.Errorf("network %d: expected node %d to be removed", n, ni)
+			}
+		}
+		for _, weight := range DefaultWeightSamples {
+			before.SetWeight(weight)
+			net.SetWeight(weight)
+			for _, inputValues := range inputData {
+				expected, results := before.EvaluateAll(inputValues), net.EvaluateAll(inputValues)
+				for i := range results {
+					if results[i] != expected[i] {
+						t.Fatalf("network %d, weight %f: expected %v, got %v", n, weight, expected, results)
+					}
+				}
+			}
+		}
+	}
+	if removed == 0 {
+		t.Error("expected some nodes to be removed")
+	}
+}
@@ -0,0 +1,209 @@
+package wann
+
+import (
+	"sort"
+)
+
+// species is a group of networks with similar topologies, that mainly compete with each other
+type species struct {
+	representative *Network // New networks are compared with this network
+	members        []int    // Indices into the population
+}
+
+// CompatibilityDistance measures how different the topologies of two networks are, as in NEAT.
+// Nodes are aligned by innovation number, as for Crossover, so nodes and connections are the same in
+// both networks if they have the same innovation numbers, even if the node indices differ.
+// The distance is the fraction of connections that only one of the networks has, plus half the fraction
+// of shared nodes with different activation functions. It is 0 for networks with the same topology.
+func CompatibilityDistance(a, b *Network) float64 {
+
+	// How much should differing connections matter?
+	const connectionCoefficient = 1.0
+
+	// How much should differing activation functions matter?
+	const activationFunctionCoefficient = 0.5
+
+	// Collect the connections of network a, from the innovation number of an input node to that of a node
+	connections := make(map[[2]int]bool)
+	for _, node := range a.AllNodes {
+		for _, inputNodeIndex := range node.InputNodes {
+			connections[[2]int{a.AllNodes[inputNodeIndex].Innovation, node.Innovation}] = true
+		}
+	}
+	connectionsA := len(connections)
+
+	// Count the connections that are only in one of the networks
+	connectionsB, shared := 0, 0
+	for _, node := range b.AllNodes {
+		for _, inputNodeIndex := range node.InputNodes {
+			connectionsB++
+			if connections[[2]int{b.AllNodes[inputNodeIndex].Innovation, node.Innovation}] {
+				shared++
+			}
+		}
+	}
+	disjoint := (connectionsA - shared) + (connectionsB - shared)
+	largest := connectionsA
+	if connectionsB > largest {
+		largest = connectionsB
+	}
+	connectionDistance := 0.0
+	if largest > 0 {
+		connectionDistance = float64(disjoint) / float64(largest)
+	}
+
+	// Count the shared nodes with different activation functions, except for the input nodes and the bias node,
+	// since their activation functions are never used
+	activationFunctions := make(map[int]ActivationFunctionIndex, len(a.AllNodes))
+	for i, node := range a.AllNodes {
+		if !a.IsInput(NeuronIndex(i)) && !a.IsBias(NeuronIndex(i)) {
+			activationFunctions[node.Innovation] = node.ActivationFunction
+		}
+	}
+	sharedNodes, differentActivationFunctions := 0, 0
+	for i, node := range b.AllNodes {
+		activationFunction, ok := activationFunctions[node.Innovation]
+		if !ok || b.IsInput(NeuronIndex(i)) || b.IsBias(NeuronIndex(i)) {
+			continue
+		}
+		sharedNodes++
+		if activationFunction != node.ActivationFunction {
+			differentActivationFunctions++
+		}
+	}
+	activationFunctionDistance := 0.0
+	if sharedNodes > 0 {
+		activationFunctionDistance = float64(differentActivationFunctions) / float64(sharedNodes)
+	}
+
+	return connectionDistance*connectionCoefficient + activationFunctionDistance*activationFunctionCoefficient
+}
+
+// speciate divides the population into species, by comparing each network with the representative of each
+// species from the previous generation. A new species is created for each network that is not compatible with
+// any of them. Species without members are removed, and a random member becomes the new representative.
+func (config *Config) speciate(population []*Network, previous []*species) []*species {
+	speciesList := make([]*species, 0, len(previous))
+	for _, s := range previous {
+		speciesList = append(speciesList, &species{representative: s.representative})
+	}
+	for i, net := range population {
+		compatible := false
+		for _, s := range speciesList {
+			if CompatibilityDistance(net, s.representative) < config.CompatibilityThreshold {
+				s.members = append(s.members, i)
+				compatible = true
+				break
+			}
+		}
+		if !compatible {
+			speciesList = append(speciesList, &species{representative: net, members: []int{i}})
+		}
+	}
+	result := make([]*species, 0, len(speciesList))
+	for _, s := range speciesList {
+		if len(s.members) == 0 {
+			continue
+		}
+		s.representative = population[s.members[config.Rand.Intn(len(s.members))]]
+		result = append(result, s)
+	}
+	return result
+}
+
+// speciesOffspring returns how many networks each species should have in the next generation.
+// The scores are shared within each species (divided by the number of members), so that a
+// species can not take over the population just by being large, and the offspring are divided
+// in proportion to the sum of the shared scores for each species.
+func speciesOffspring(speciesList []*species, scores map[int]float64, populationSize int) []int {
+	// The shared scores must not be negative, so shift them by the lowest score
+	lowest := 0.0
+	for _, score := range scores {
+		if score < lowest {
+			lowest = score
+		}
+	}
+	sharedScores := make([]float64, len(speciesList))
+	total := 0.0
+	for si, s := range speciesList {
+		for _, i := range s.members {
+			sharedScores[si] += (scores[i] - lowest) / float64(len(s.members))
+		}
+		total += sharedScores[si]
+	}
+	offspring := make([]int, len(speciesList))
+	remainders := make([]float64, len(speciesList))
+	assigned := 0
+	for si := range speciesList {
+		// Divide the population evenly if all the scores are the same
+		quota := float64(populationSize) / float64(len(speciesList))
+		if total > 0 {
+			quota = float64(populationSize) * sharedScores[si] / total
+		}
+		offspring[si] = int(quota)
+		remainders[si] = quota - float64(offspring[si])
+		assigned += offspring[si]
+	}
+	// Give the rest of the offspring to the species with the largest remainders
+	order := make([]int, len(speciesList))
+	for si := range order {
+		order[si] = si
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for k := 0; assigned < populationSize; k++ {
+		offspring[order[k%len(order)]]++
+		assigned++
+	}
+	return offspring
+}
+
+// reproduceSpecies creates the next generation of networks. Each species that gets offspring keeps its best
+// network, and the rest of its offspring are modified copies or crossovers of the best networks within the same species.
+// If config.Selector is set, it is used for picking the parents within each species, but only the best network is kept.
+func (config *Config) reproduceSpecies(population []*Network, scores map[int]float64, speciesList []*species, maxModificationIterations int) []*Network {
+
+	// The fraction of each species that may have offspring
+	const survivalFraction = 0.2
+
+	offspring := speciesOffspring(speciesList, scores, len(population))
+	nextPopulation := make([]*Network, 0, len(population))
+	for si, s := range speciesList {
+		if offspring[si] == 0 {
+			// The species dies out
+			continue
+		}
+		// Sort the members by score, in descending order
+		members := make([]int, len(s.members))
+		copy(members, s.members)
+		sort.SliceStable(members, func(a, b int) bool {
+			return scores[members[a]] > scores[members[b]]
+		})
+		parents := make([]*Network, len(members))
+		memberScores := make([]float64, len(members))
+		for k, i := range members {
+			parents[k] = population[i]
+			memberScores[k] = scores[i]
+		}
+		// Pick parents among the best members, or with the configured selection strategy
+		survivors := int(float64(len(members)) * survivalFraction)
+		if survivors < 1 {
+			survivors = 1
+		}
+		pick := func() int {
+			return config.Rand.Intn(survivors)
+		}
+		if config.Selector != nil {
+			pick = func() int {
+				return config.Selector.Select(memberScores, config.Rand)
+			}
+		}
+		// Keep the best network of the species as it is
+		nextPopulation = append(nextPopulation, population[members[0]])
+		for k := 1; k < offspring[si]; k++ {
+			nextPopulation = append(nextPopulation, config.offspring(parents, pick, maxModificationIterations))
+		}
+	}
+	return nextPopulation
+}
@@ -0,0 +1,119 @@
+package wann
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCompatibilityDistance(t *testing.T) {
+	rand.Seed(commonSeed)
+	net := NewNetwork(&Config{
+		inputs:                 5,
+		InitialConnectionRatio: 0.5,
+	})
+	net.UpdateNetworkPointers()
+	if d := CompatibilityDistance(&net, &net); d != 0.0 {
+		t.Errorf("expected a distance of 0 to itself, got %f", d)
+	}
+	modified := net.Copy()
+	if d := CompatibilityDistance(&net, modified); d != 0.0 {
+		t.Errorf("expected a distance of 0 to a copy, got %f", d)
+	}
+	for i := 0; i < 5; i++ {
+		modified.Modify(10)
+	}
+	a, b := CompatibilityDistance(&net, modified), CompatibilityDistance(modified, &net)
+	if a <= 0.0 {
+		t.Errorf("expected a positive distance to a modified copy, got %f", a)
+	}
+	if a != b {
+		t.Errorf("expected the distance to be symmetric, got %f and %f", a, b)
+	}
+}
+
+func TestCompatibilityDistanceIndices(t *testing.T) {
+	config := &Config{
+		inputs:                 3,
+		InitialConnectionRatio: 1.0,
+		RandomSeed:             commonSeed,
+	}
+	config.Init()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for len(net.AllNodes) < len(net.InputNodes)+4 {
+		net.InsertRandomNode()
+	}
+	// Swap the last two nodes, which are hidden nodes, so that the same nodes and connections have other indices
+	swapped := net.Copy()
+	i, j := NeuronIndex(len(swapped.AllNodes)-2), NeuronIndex(len(swapped.AllNodes)-1)
+	swapped.AllNodes[i], swapped.AllNodes[j] = swapped.AllNodes[j], swapped.AllNodes[i]
+	for k := range swapped.AllNodes {
+		node := &swapped.AllNodes[k]
+		node.neuronIndex = NeuronIndex(k)
+		for l, inputNodeIndex := range node.InputNodes {
+			switch inputNodeIndex {
+			case i:
+				node.InputNodes[l] = j
+			case j:
+				node.InputNodes[l] = i
+			}
+		}
+	}
+	swapped.UpdateNetworkPointers()
+	if swapped.AllNodes[i].Innovation == net.AllNodes[i].Innovation {
+		t.Fatal("expected the swapped nodes to have other innovation numbers")
+	}
+	if d := CompatibilityDistance(&net, swapped); d != 0.0 {
+		t.Errorf("expected a distance of 0 to a network with the same nodes at other indices, got %f", d)
+	}
+	if a, b := net.Evaluate([]float64{0.1, 0.2, 0.3}), swapped.Evaluate([]float64{0.1, 0.2, 0.3}); a != b {
+		t.Errorf("expected the networks to give the same result, got %f and %f", a, b)
+	}
+}
+
+func TestSpeciesOffspring(t *testing.T) {
+	speciesList := []*species{
+		{members: []int{0, 1, 2, 3}},
+		{members: []int{4}},
+		{members: []int{5, 6}},
+	}
+	scores := map[int]float64{0: 1.0, 1: 1.0, 2: 1.0, 3: 1.0, 4: 4.0, 5: -1.0, 6: -1.0}
+	offspring := speciesOffspring(speciesList, scores, 10)
+	// The shared scores are 2, 5 and 0, after shifting the scores by -1
+	expected := []int{3, 7, 0}
+	for i := range expected {
+		if offspring[i] != expected[i] {
+			t.Fatalf("expected offspring %v, got %v", expected, offspring)
+		}
+	}
+}
+
+func TestSpeciation(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	maxSpecies := 0
+	config := &Config{
+		InitialConnectionRatio: 0.5,
+		Generations:            10,
+		PopulationSize:         40,
+		RandomSeed:             commonSeed,
+		CompatibilityThreshold: 0.3,
+		OnGeneration: func(stats GenerationStats) {
+			if len(stats.Complexities) != 40 {
+				t.Errorf("generation %d: expected a population of 40, got %d", stats.Generation, len(stats.Complexities))
+			}
+			if stats.Species > maxSpecies {
+				maxSpecies = stats.Species
+			}
+		},
+	}
+	if _, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0}); err != nil {
+		t.Fatal(err)
+	}
+	if maxSpecies < 2 {
+		t.Errorf("expected the population to be divided into several species, got %d", maxSpecies)
+	}
+}
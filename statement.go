@@ -32,351 +32,149 @@ func (net Network) SetInputValues(inputValues []float64) {
 	}
 }
 
-// NetworkStatementWithInputValues will print out a trace of visiting all nodes from output and to the left
+// NetworkStatementWithInputValues will trace all nodes from this node and to the left, and return a statement
+// where the network input nodes are replaced with their values. The traced nodes are added to visited.
 func (neuron Neuron) NetworkStatementWithInputValues(visited *[]NeuronIndex) (*jen.Statement, error) {
-	// First guard against re-visits
-	if neuron.neuronIndex.In(visited) {
-		return jen.Empty(), errors.New("already visited: " + strconv.Itoa(int(neuron.neuronIndex)))
-	}
-	*visited = append(*visited, neuron.neuronIndex)
-
-	// Toggle bits in neuronType to signify what type of neuron it is
-	neuronType := 0
-	if neuron.IsOutput() {
-		neuronType ^= 1
-	}
-	if neuron.IsInput() {
-		neuronType ^= 2
-	}
-
-	// Switch on the neuronType bits
-	switch neuronType {
-	case 0: // not network output and not network input, may have input nodes
-		//fmt.Println("* Middle node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			//fmt.Println("No input nodes to this node, and not a network input node.")
-			return jen.Empty(), errIgnore
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputValues(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputValues(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
-		}
-	case 1: // network output and not network input, may have input nodes
-		//fmt.Println("* Network output node and not network input node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			//fmt.Println("No input nodes to this node, and not a network input node.")
-			return jen.Empty(), errIgnore
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputValues(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputValues(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
-		}
-	case 2: // not network output, but network input, may not have input nodes
-		//fmt.Println("* Network input node and not network output node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			if neuron.Value == nil {
-				panic("implementation error: network input Value is nil")
-			}
-			// No inputs to this network input node, return the Value
-			//fmt.Println("literal", *neuron.Value, "activation function", neuron.ActivationFunction.Name())
-			inner := jen.Lit(*neuron.Value)
-			return neuron.ActivationFunction.Statement(inner), nil
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputValues(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputValues(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
-		}
-	case 3: // network output and network input, may have input nodes
-		//fmt.Println("* Network input and output node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			if neuron.Value == nil {
-				panic("implementation error: network input Value is nil")
-			}
-			// No inputs to this network input node, return the Value
-			//fmt.Println("literal", *neuron.Value)
-			return jen.Lit(*neuron.Value), nil
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputValues(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputValues(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
-		}
-	}
-	panic("implementation error")
+	return neuron.networkStatement(visited, neuron.Net.inputValueStatement)
 }
 
 // StatementWithInputValues traces the entire network
 func (net *Network) StatementWithInputValues() (*jen.Statement, error) {
-	//fmt.Println("=== Trace ===")
-	visited := make([]NeuronIndex, 0)
-	outputNode := net.AllNodes[net.OutputNode]
-	statement, err := outputNode.NetworkStatementWithInputValues(&visited)
+	statements, err := net.outputStatements([]NeuronIndex{net.OutputNode}, net.inputValueStatement)
 	if err != nil {
 		return jen.Empty(), err
 	}
-	return statement, nil
+	return statements[0], nil
+}
+
+// StatementsWithInputValues traces the entire network, returning one statement per output node
+func (net *Network) StatementsWithInputValues() ([]*jen.Statement, error) {
+	return net.outputStatements(net.Outputs(), net.inputValueStatement)
 }
 
-// NetworkStatementWithInputDataVariables will print out a trace of visiting all nodes from output and to the left,
-// but with the given slice of statements instead of using the input values
+// NetworkStatementWithInputDataVariables will trace all nodes from this node and to the left, and return a
+// statement where the network input nodes are replaced with "inputData[i]". The traced nodes are added to visited.
 func (neuron Neuron) NetworkStatementWithInputDataVariables(visited *[]NeuronIndex) (*jen.Statement, error) {
-	// First guard against re-visits
+	return neuron.networkStatement(visited, neuron.Net.inputDataStatement)
+}
+
+// StatementWithInputDataVariables traces the entire network, using statements for the input numbers
+func (net *Network) StatementWithInputDataVariables() (*jen.Statement, error) {
+	statements, err := net.outputStatements([]NeuronIndex{net.OutputNode}, net.inputDataStatement)
+	if err != nil {
+		return jen.Empty(), err
+	}
+	return statements[0], nil
+}
+
+// StatementsWithInputDataVariables traces the entire network, using statements for the input numbers,
+// returning one statement per output node
+func (net *Network) StatementsWithInputDataVariables() ([]*jen.Statement, error) {
+	return net.outputStatements(net.Outputs(), net.inputDataStatement)
+}
+
+// inputValueStatement returns the value of the given input node as a statement, or 0 if it has no value
+func (net *Network) inputValueStatement(ni NeuronIndex) (*jen.Statement, error) {
+	if value := net.AllNodes[ni].Value; value != nil {
+		return jen.Lit(*value), nil
+	}
+	return jen.Lit(0.0), nil
+}
+
+// inputDataStatement returns a statement like "inputData[0]" for the given input node
+func (net *Network) inputDataStatement(ni NeuronIndex) (*jen.Statement, error) {
+	return net.AllNodes[ni].InputStatement()
+}
+
+// networkStatement traces all nodes from this node and to the left, and adds them to visited.
+// Returns an error if this node has already been visited.
+func (neuron Neuron) networkStatement(visited *[]NeuronIndex, inputStatement func(ni NeuronIndex) (*jen.Statement, error)) (*jen.Statement, error) {
 	if neuron.neuronIndex.In(visited) {
 		return jen.Empty(), errors.New("already visited: " + strconv.Itoa(int(neuron.neuronIndex)))
 	}
-	*visited = append(*visited, neuron.neuronIndex)
-
-	// Toggle bits in neuronType to signify what type of neuron it is
-	neuronType := 0
-	if neuron.IsOutput() {
-		neuronType ^= 1
+	net := neuron.Net
+	statements := make([]*jen.Statement, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	statement, err := net.nodeStatement(neuron.neuronIndex, inputStatement, statements, states)
+	for i, state := range states {
+		if state != nodeUnvisited {
+			*visited = append(*visited, NeuronIndex(i))
+		}
 	}
-	if neuron.IsInput() {
-		neuronType ^= 2
+	return statement, err
+}
+
+// outputStatements traces the network from each of the given output nodes, and returns one statement per output node
+func (net *Network) outputStatements(outputs []NeuronIndex, inputStatement func(ni NeuronIndex) (*jen.Statement, error)) ([]*jen.Statement, error) {
+	statements := make([]*jen.Statement, len(net.AllNodes))
+	states := make([]int, len(net.AllNodes))
+	results := make([]*jen.Statement, len(outputs))
+	for i, outputNodeIndex := range outputs {
+		statement, err := net.nodeStatement(outputNodeIndex, inputStatement, statements, states)
+		if err != nil {
+			return []*jen.Statement{}, err
+		}
+		results[i] = statement
 	}
+	return results, nil
+}
 
-	// Switch on the neuronType bits
-	switch neuronType {
-	case 0: // not network output and not network input, may have input nodes
-		//fmt.Println("* Middle node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			//fmt.Println("No input nodes to this node, and not a network input node.")
-			return jen.Empty(), errIgnore
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputDataVariables(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputDataVariables(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%#v\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
+// nodeStatement returns a statement for the given node, that is built in the same way as evaluateNode evaluates the
+// node, so that the statement gives the same result as Evaluate. The input nodes of the network are replaced with the
+// statements from inputStatement. Each node is traced once, and its statement is kept in statements and reused by the
+// other nodes that depend on it. A connection that closes a cycle is left out, and errIgnore is returned for nodes
+// without a result.
+func (net *Network) nodeStatement(nodeIndex NeuronIndex, inputStatement func(ni NeuronIndex) (*jen.Statement, error), statements []*jen.Statement, states []int) (*jen.Statement, error) {
+	switch states[nodeIndex] {
+	case nodeVisited:
+		return statements[nodeIndex].Clone(), nil
+	case nodeEmpty:
+		return jen.Empty(), errIgnore
+	}
+	states[nodeIndex] = nodeVisiting
+	neuron := &net.AllNodes[nodeIndex]
+	var inputStatements []*jen.Statement
+	counter := 0
+	for _, inputNeuronIndex := range neuron.InputNodes {
+		if int(inputNeuronIndex) >= len(net.AllNodes) {
+			continue
 		}
-	case 1: // network output and not network input, may have input nodes
-		//fmt.Println("* Network output node and not network input node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			//fmt.Println("No input nodes to this node, and not a network input node.")
-			return jen.Empty(), errIgnore
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputDataVariables(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputDataVariables(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
+		counter++
+		if states[inputNeuronIndex] == nodeVisiting {
+			// This connection closes a cycle, and uses 0.0
+			continue
 		}
-	case 2: // not network output, but network input, may not have input nodes
-		//fmt.Println("* Network input node and not network output node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			inputStatement, err := neuron.InputStatement()
-			if err != nil {
-				panic("implementation error: " + err.Error())
-			}
-			// No inputs to this network input node, return the Value
-			//fmt.Println("activation function", neuron.ActivationFunction.Name())
-			inner := inputStatement
-			return neuron.ActivationFunction.Statement(inner), nil
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputDataVariables(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputDataVariables(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
+		statement, err := net.nodeStatement(inputNeuronIndex, inputStatement, statements, states)
+		if err == errIgnore {
+			continue
 		}
-	case 3: // network output and network input, may have input nodes
-		//fmt.Println("* Network input and output node")
-		switch len(neuron.InputNodes) {
-		case 0:
-			inputStatement, err := neuron.InputStatement()
-			if err != nil {
-				panic("implementation error: " + err.Error())
-			}
-			// No inputs to this network input node, return the statement
-			inner := inputStatement
-			return inner, nil
-		case 1:
-			//fmt.Println("One input node to this node.")
-			inputNode := neuron.Net.AllNodes[neuron.InputNodes[0]]
-			statement, err := inputNode.NetworkStatementWithInputDataVariables(visited)
-			if err != nil {
-				return jen.Empty(), err
-			}
-			//fmt.Println("** Statement: ", render(statement))
-			return statement, nil
-		default:
-			var inputStatements []*jen.Statement
-			for _, inputNodeIndex := range neuron.InputNodes {
-				statement, err := neuron.Net.AllNodes[inputNodeIndex].NetworkStatementWithInputDataVariables(visited)
-				if err != nil {
-					continue
-				}
-				inputStatements = append(inputStatements, statement)
-			}
-			activationStatement := ActivationStatement(neuron.ActivationFunction, neuron.Net.Weight, inputStatements)
-			//fmt.Printf("** Statements to combine with %s:\n", neuron.ActivationFunction.Name())
-			//for _, inputStatement := range inputStatements {
-			//	fmt.Printf("\t%s\n", inputStatement)
-			//}
-			//fmt.Println("** Activation statement: ", render(activationStatement))
-			return activationStatement, nil
+		if err != nil {
+			return jen.Empty(), err
 		}
+		inputStatements = append(inputStatements, statement)
 	}
-	panic("implementation error")
-}
-
-// StatementWithInputDataVariables traces the entire network, using statements for the input numbers
-func (net *Network) StatementWithInputDataVariables() (*jen.Statement, error) {
-	//fmt.Println("=== Trace2 ===")
-	visited := make([]NeuronIndex, 0)
-	outputNode := net.AllNodes[net.OutputNode]
-	statement, err := outputNode.NetworkStatementWithInputDataVariables(&visited)
-	if err != nil {
-		return jen.Empty(), err
+	switch {
+	case counter == 0 && !neuron.IsOutput() && net.IsInput(nodeIndex):
+		statement, err := inputStatement(nodeIndex)
+		if err != nil {
+			return jen.Empty(), err
+		}
+		statements[nodeIndex] = statement
+	case counter == 0 && neuron.Value != nil && !neuron.IsOutput():
+		// For instance the bias node, which always has the value 1
+		statements[nodeIndex] = jen.Lit(*neuron.Value)
+	case counter == 0:
+		states[nodeIndex] = nodeEmpty
+		return jen.Empty(), errIgnore
+	default:
+		if len(inputStatements) == 0 {
+			// None of the input nodes have a result
+			inputStatements = append(inputStatements, jen.Lit(0.0))
+		}
+		statements[nodeIndex] = ActivationStatement(neuron.ActivationFunction, net.Weight, inputStatements)
 	}
-	return statement, nil
+	states[nodeIndex] = nodeVisited
+	return statements[nodeIndex].Clone(), nil
 }
 
 // Render renders a *jen.Statement to a string, if possible
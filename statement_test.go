@@ -2,6 +2,7 @@ package wann
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 )
@@ -139,3 +140,51 @@ func ExampleNetwork_OutputNodeStatementX() {
 	// Output:
 	// f := math.Pow(x, 2.0)
 }
+
+func TestStatementsWithInputDataVariables(t *testing.T) {
+	rand.Seed(1)
+	net := NewNetwork(&Config{
+		inputs:                 6,
+		Outputs:                2,
+		InitialConnectionRatio: 0.7,
+		sharedWeight:           0.5,
+	})
+	statements, err := net.StatementsWithInputDataVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(statements))
+	}
+}
+
+func TestStatementSameAsEvaluate(t *testing.T) {
+	config := &Config{
+		inputs:                 4,
+		InitialConnectionRatio: 0.7,
+		Bias:                   true,
+		RandomSeed:             commonSeed,
+		// Sigmoid, Swish, SoftPlus and Gauss are approximated when evaluating, but not in the statements
+		AllowedActivationFunctions: []ActivationFunctionIndex{Linear, Inv, Abs, ReLU, Step, Sin, Cos, Tanh, Squared},
+	}
+	config.initRandom()
+	net := NewNetwork(config)
+	net.UpdateNetworkPointers()
+	for i := 0; i < 10; i++ {
+		net.ModifyWith(MutationRates{InsertNode: 1.0, AddConnection: 1.0, ChangeActivationFunction: 1.0}, 10)
+	}
+	net.SetWeight(0.5)
+	statement, err := net.StatementWithInputDataVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, inputValues := range [][]float64{{0.0, 1.0, 0.0, 1.0}, {-0.5, 0.25, 1.0, 0.75}} {
+		result, err := RunStatementInputData(statement, inputValues)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := net.Evaluate(inputValues); math.Abs(result-expected) > 1e-9 {
+			t.Errorf("expected %v, got %v from the statement %s", expected, result, Render(statement))
+		}
+	}
+}
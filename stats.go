@@ -0,0 +1,47 @@
+package wann
+
+import (
+	"sort"
+	"time"
+)
+
+// GenerationStats contains statistics about a generation that has just been scored.
+// It is passed to Config.OnGeneration, for observing the evolution as it goes.
+type GenerationStats struct {
+	// The generation number, starting at 0
+	Generation int
+	// The best, average and worst score of the networks in this generation
+	BestScore    float64
+	AverageScore float64
+	WorstScore   float64
+	// The shared weights that the networks were scored with, in this generation
+	Weights []float64
+	// The score of the best network in this generation, for each of the shared weights, before aggregation
+	WeightScores []float64
+	// The best score so far, for all generations
+	AllTimeBestScore float64
+	// The best network so far, for all generations. It must not be modified.
+	BestNetwork *Network
+	// The number of nodes that are connected to the output nodes of the best network so far
+	ConnectedNodes int
+	// The complexity of each network in this generation, sorted from the least to the most complex
+	Complexities []float64
+	// The number of species, if speciation is enabled
+	Species int
+	// The number of networks in the first Pareto front, if multi-objective optimisation is enabled
+	ParetoFront int
+	// For how many generations the best score has not improved
+	NoImprovementCounter int
+	// The time since the evolution was started
+	Elapsed time.Duration
+}
+
+// complexities returns the complexity of each network in the population, sorted in ascending order
+func complexities(population []*Network) []float64 {
+	result := make([]float64, len(population))
+	for i, net := range population {
+		result[i] = net.Complexity()
+	}
+	sort.Float64s(result)
+	return result
+}
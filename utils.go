@@ -14,11 +14,16 @@ type Pair struct {
 // PairList is a slice of Pair
 type PairList []Pair
 
-func (p PairList) Len() int           { return len(p) }
-func (p PairList) Less(i, j int) bool { return p[i].Value < p[j].Value }
-func (p PairList) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p PairList) Len() int      { return len(p) }
+func (p PairList) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
 
-// SortByValue sorts a map[int]float64 by value
+// Less orders pairs with equal values by key, so that the sorting does not depend on the map order
+func (p PairList) Less(i, j int) bool {
+	return p[i].Value < p[j].Value || (p[i].Value == p[j].Value && p[i].Key > p[j].Key)
+}
+
+// SortByValue sorts a map[int]float64 by value, in descending order.
+// Pairs with equal values are sorted by key, in ascending order.
 func SortByValue(m map[int]float64) PairList {
 	pl := make(PairList, len(m))
 	i := 0
@@ -0,0 +1,108 @@
+package wann
+
+import (
+	"math"
+)
+
+// Aggregation is a number that represents a way of combining the scores that
+// a network gets for each of the shared weights into a single score
+type Aggregation int
+
+const (
+	// AggregateMean uses the mean score over all the shared weights
+	AggregateMean Aggregation = iota
+	// AggregateMin uses the lowest score, which rewards networks that do well for every shared weight
+	AggregateMin
+	// AggregateMax uses the highest score, which rewards networks that do very well for at least one shared weight
+	AggregateMax
+	// AggregateMeanMax uses the average of the mean score and the highest score, which rewards networks that do
+	// well both on average and for their best shared weight. The paper ranks the networks by the mean and the
+	// highest score as separate objectives instead, which is done when Config.MultiObjective is set.
+	AggregateMeanMax
+)
+
+// DefaultWeightSamples is the series of shared weights that is used in the paper
+var DefaultWeightSamples = []float64{-2.0, -1.0, -0.5, 0.5, 1.0, 2.0}
+
+// Name returns a name for each way of aggregating scores
+func (a Aggregation) Name() string {
+	switch a {
+	case AggregateMean:
+		return "mean"
+	case AggregateMin:
+		return "min"
+	case AggregateMax:
+		return "max"
+	case AggregateMeanMax:
+		return "mean+max"
+	default:
+		return "unknown"
+	}
+}
+
+// Aggregate combines the given scores into one score
+func (a Aggregation) Aggregate(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0.0
+	}
+	sum := 0.0
+	lowest := math.Inf(1)
+	highest := math.Inf(-1)
+	for _, score := range scores {
+		sum += score
+		lowest = math.Min(lowest, score)
+		highest = math.Max(highest, score)
+	}
+	mean := sum / float64(len(scores))
+	switch a {
+	case AggregateMin:
+		return lowest
+	case AggregateMax:
+		return highest
+	case AggregateMeanMax:
+		return (mean + highest) / 2.0
+	default:
+		return mean
+	}
+}
+
+// scoreWeights scores the population once per given shared weight, and then aggregates the scores for each network.
+// It returns a map with scores, the sum of scores and a map with the best of the given weights for each network.
+func (config *Config) scoreWeights(population []*Network, weights []float64, scoreWithWeight func(population []*Network, weight float64) (map[int]float64, float64)) (map[int]float64, float64, map[int]float64) {
+	return config.aggregateScores(scoreWeightSeries(population, weights, scoreWithWeight), weights)
+}
+
+// scoreWeightSeries scores the population once per given shared weight.
+// It returns the scores for each network, with one score per weight.
+func scoreWeightSeries(population []*Network, weights []float64, scoreWithWeight func(population []*Network, weight float64) (map[int]float64, float64)) [][]float64 {
+	weightScores := make([][]float64, len(population))
+	for _, w := range weights {
+		scoreMap, _ := scoreWithWeight(population, w)
+		for i := range population {
+			weightScores[i] = append(weightScores[i], scoreMap[i])
+		}
+	}
+	return weightScores
+}
+
+// aggregateScores combines the scores for each weight into one score per network, using config.WeightAggregation.
+// It returns a map with scores, the sum of scores and a map with the best of the given weights for each network.
+func (config *Config) aggregateScores(weightScores [][]float64, weights []float64) (map[int]float64, float64, map[int]float64) {
+	scoreMap := make(map[int]float64, len(weightScores))
+	scoreSum := 0.0
+	bestWeights := make(map[int]float64, len(weightScores))
+	for i := range weightScores {
+		score := config.WeightAggregation.Aggregate(weightScores[i])
+		scoreSum += score
+		scoreMap[i] = score
+		bestWeights[i] = weights[Argmax(weightScores[i])]
+	}
+	return scoreMap, scoreSum, bestWeights
+}
+
+// WeightScores returns the score of the network that was returned by the last evolution, for each of
+// config.WeightSamples, in the same order. The scores are found after the best weight for the network has been
+// found, so they can be compared with that one. Returns nil if the evolution was stopped before that.
+func (config *Config) WeightScores() []float64 {
+	return config.weightScores
+}
@@ -0,0 +1,65 @@
+package wann
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleAggregation_Aggregate() {
+	scores := []float64{0.5, 1.0, 3.0, 1.5}
+	for _, a := range []Aggregation{AggregateMean, AggregateMin, AggregateMax, AggregateMeanMax} {
+		fmt.Printf("%s: %.3f\n", a.Name(), a.Aggregate(scores))
+	}
+	// Output:
+	// mean: 1.500
+	// min: 0.500
+	// max: 3.000
+	// mean+max: 2.250
+}
+
+func TestWeightScores(t *testing.T) {
+	inputData := [][]float64{
+		{0.0, 1.0, 0.0, 1.0, 1.0, 1.0},
+		{1.0, 1.0, 1.0, 0.0, 1.0, 0.0},
+		{1.0, 1.0, 1.0, 0.0, 0.0, 1.0},
+	}
+	generations := 0
+	config := &Config{
+		InitialConnectionRatio: 0.3,
+		Generations:            4,
+		PopulationSize:         30,
+		WeightSamples:          DefaultWeightSamples,
+		WeightAggregation:      AggregateMin,
+		RandomSeed:             commonSeed,
+		OnGeneration: func(stats GenerationStats) {
+			generations++
+			if len(stats.Weights) != len(DefaultWeightSamples) || len(stats.WeightScores) != len(stats.Weights) {
+				t.Errorf("generation %d: expected one score per weight, got %v for %v", stats.Generation, stats.WeightScores, stats.Weights)
+				return
+			}
+			if score := AggregateMin.Aggregate(stats.WeightScores); score != stats.BestScore {
+				t.Errorf("generation %d: expected the aggregated weight scores to be the best score %v, got %v", stats.Generation, stats.BestScore, score)
+			}
+		},
+	}
+	net, err := config.Evolve(inputData, []float64{1.0, -1.0, -1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generations != config.Generations {
+		t.Errorf("expected %d generations, got %d", config.Generations, generations)
+	}
+	// The scores of the returned network, for each weight sample
+	weightScores := config.WeightScores()
+	if len(weightScores) != len(DefaultWeightSamples) {
+		t.Fatalf("expected one score per weight sample, got %v", weightScores)
+	}
+	fitness := MultiplierFitness(inputData, []float64{1.0, -1.0, -1.0})
+	for i, w := range DefaultWeightSamples {
+		scored := net.Copy()
+		scored.SetWeight(w)
+		if score := fitness(scored); score != weightScores[i] {
+			t.Errorf("weight %f: expected the score %v, got %v", w, score, weightScores[i])
+		}
+	}
+}